@@ -34,13 +34,22 @@ func NewAttribute(name string, value interface{}) Attribute {
 
 func (r *attr) Match(messageContext ...MessageContext) bool {
 	for _, c := range messageContext {
-		if e, ok := c.(Attribute); ok && e.Name() == r.name && reflect.DeepEqual(e.Value(), r.value) {
+		if e, ok := c.(Attribute); ok && e.Name() == r.name && r.matchValue(e.Value()) {
 			return true
 		}
 	}
 	return false
 }
 
+// matchValue matches the value of an attribute of a message context.
+// Numbers of different types are compared by their numeric value.
+func (r *attr) matchValue(v interface{}) bool {
+	if m, ok := r.value.(ValueMatcher); ok {
+		return m.MatchValue(v)
+	}
+	return reflect.DeepEqual(v, r.value) || numericEqual(v, r.value)
+}
+
 func (r *attr) Attach(l Logger) Logger {
 	return l.WithValues(r.name, r.value)
 }
@@ -79,6 +79,10 @@ func (d *attributionContext) Match(cond Condition) bool {
 	return cond.Match(append(d.ctx.GetMessageContext(), d.messageContext...))
 }
 
+func (d *attributionContext) Explain(messageContext ...MessageContext) *Explanation {
+	return d.ctx.Explain(sliceAppend(d.messageContext, messageContext...))
+}
+
 func (d *attributionContext) Logger(messageContext ...MessageContext) Logger {
 	l := d.ctx.Logger(sliceAppend(d.messageContext, messageContext...))
 
@@ -0,0 +1,120 @@
+/*
+ * Copyright 2023 Mandelsoft. All rights reserved.
+ *  This file is licensed under the Apache Software License, v. 2 except as noted
+ *  otherwise in the LICENSE file
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package logging_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-logr/logr"
+
+	"github.com/mandelsoft/logging"
+)
+
+// uncached is a message context element without canonical
+// representation, which disables the evaluation cache.
+type uncached struct{}
+
+func benchmarkContext(rules, depth int) logging.Context {
+	ctx := logging.New(logr.Discard())
+	for i := 0; i < rules; i++ {
+		ctx.AddRule(logging.NewConditionRule(logging.DebugLevel, logging.NewRealm(fmt.Sprintf("realm%d", i))))
+	}
+	for i := 1; i < depth; i++ {
+		ctx = logging.NewWithBase(ctx)
+	}
+	return ctx
+}
+
+func BenchmarkLogger(b *testing.B) {
+	realm := logging.NewRealm("unknown")
+	tag := logging.NewTag("tag")
+
+	for _, rules := range []int{1, 10, 100} {
+		for _, depth := range []int{1, 5} {
+			ctx := benchmarkContext(rules, depth)
+			b.Run(fmt.Sprintf("rules=%d/depth=%d/cached", rules, depth), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					ctx.Logger(realm, tag)
+				}
+			})
+			b.Run(fmt.Sprintf("rules=%d/depth=%d/uncached", rules, depth), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					ctx.Logger(realm, tag, uncached{})
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkRealmRules(b *testing.B) {
+	for _, rules := range []int{10, 100, 1000} {
+		ctx := benchmarkContext(rules, 1)
+		realm := logging.NewRealm("realm0/sub")
+		b.Run(fmt.Sprintf("rules=%d", rules), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				ctx.Logger(realm, uncached{})
+			}
+		})
+	}
+}
+
+func BenchmarkParallelGetSink(b *testing.B) {
+	ctx := benchmarkContext(10, 3)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			ctx.GetSink()
+		}
+	})
+}
+
+func BenchmarkParallelGetDefaultLevel(b *testing.B) {
+	ctx := benchmarkContext(10, 3)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			ctx.GetDefaultLevel()
+		}
+	})
+}
+
+func BenchmarkParallelLogger(b *testing.B) {
+	realm := logging.NewRealm("realm5")
+	ctx := benchmarkContext(10, 3)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			ctx.Logger(realm, uncached{}).Enabled(logging.DebugLevel)
+		}
+	})
+}
+
+func BenchmarkParallelLoggerWithUpdates(b *testing.B) {
+	realm := logging.NewRealm("realm5")
+	ctx := benchmarkContext(10, 3)
+	root := ctx.Tree().GetBaseContext().Tree().GetBaseContext()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			i++
+			if i%1000 == 0 {
+				root.SetDefaultLevel(logging.InfoLevel)
+			}
+			ctx.Logger(realm).Enabled(logging.DebugLevel)
+		}
+	})
+}
@@ -0,0 +1,123 @@
+/*
+ * Copyright 2023 Mandelsoft. All rights reserved.
+ *  This file is licensed under the Apache Software License, v. 2 except as noted
+ *  otherwise in the LICENSE file
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package logging
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// maxCacheEntries limits the number of cached evaluation
+// results of a logging context.
+const maxCacheEntries = 4096
+
+// evaluationCache caches the loggers determined for message contexts
+// for a dedicated watermark of a logging context.
+// Any change of the context tree moves the watermark and therefore
+// invalidates the cache.
+type evaluationCache struct {
+	watermark int64
+	size      atomic.Int64
+	entries   sync.Map
+}
+
+func newEvaluationCache(watermark int64) *evaluationCache {
+	return &evaluationCache{watermark: watermark}
+}
+
+func (c *evaluationCache) get(key string) Logger {
+	if l, ok := c.entries.Load(key); ok {
+		return l.(Logger)
+	}
+	return nil
+}
+
+func (c *evaluationCache) put(key string, l Logger) {
+	if c.size.Load() >= maxCacheEntries {
+		return
+	}
+	if _, loaded := c.entries.LoadOrStore(key, l); !loaded {
+		c.size.Add(1)
+	}
+}
+
+// cacheKey provides a canonical string representation of a flattened
+// message context. The second result is false, if the message
+// context contains elements without a canonical representation.
+// Such message contexts cannot be cached.
+func cacheKey(messageContext []MessageContext) (string, bool) {
+	var key strings.Builder
+
+	for _, m := range messageContext {
+		switch e := m.(type) {
+		case Realm:
+			key.WriteString("r")
+			key.WriteString(string(e))
+		case RealmPrefix:
+			key.WriteString("p")
+			key.WriteString(string(e))
+		case Tag:
+			key.WriteString("t")
+			key.WriteString(string(e))
+		case Name:
+			key.WriteString("n")
+			key.WriteString(string(e))
+		case *attr:
+			v, ok := canonicalValue(e.value)
+			if !ok {
+				return "", false
+			}
+			key.WriteString("a")
+			key.WriteString(e.name)
+			key.WriteString("=")
+			key.WriteString(v)
+		default:
+			return "", false
+		}
+		key.WriteByte(0)
+	}
+	return key.String(), true
+}
+
+func canonicalValue(v interface{}) (string, bool) {
+	switch e := v.(type) {
+	case string:
+		return "s" + e, true
+	case bool:
+		return "b" + strconv.FormatBool(e), true
+	case int:
+		return "i" + strconv.FormatInt(int64(e), 10), true
+	case int32:
+		return "i32" + strconv.FormatInt(int64(e), 10), true
+	case int64:
+		return "i64" + strconv.FormatInt(e, 10), true
+	case uint:
+		return "u" + strconv.FormatUint(uint64(e), 10), true
+	case uint32:
+		return "u32" + strconv.FormatUint(uint64(e), 10), true
+	case uint64:
+		return "u64" + strconv.FormatUint(e, 10), true
+	case float64:
+		return "f" + strconv.FormatFloat(e, 'g', -1, 64), true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright 2023 Mandelsoft. All rights reserved.
+ *  This file is licensed under the Apache Software License, v. 2 except as noted
+ *  otherwise in the LICENSE file
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package logging_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/tonglil/buflogr"
+
+	"github.com/mandelsoft/logging"
+)
+
+var _ = Describe("evaluation cache", func() {
+	var buf bytes.Buffer
+	var ctx logging.Context
+
+	realm := logging.NewRealm("realm")
+	tag := logging.NewTag("tag")
+
+	BeforeEach(func() {
+		buf.Reset()
+		ctx = logging.New(buflogr.NewWithBuffer(&buf))
+	})
+
+	It("reuses evaluated loggers", func() {
+		ctx.AddRule(logging.NewConditionRule(logging.DebugLevel, realm))
+		l := ctx.Logger(realm, tag)
+		Expect(ctx.Logger(realm, tag)).To(BeIdenticalTo(l))
+		Expect(ctx.Logger(tag, realm)).NotTo(BeIdenticalTo(l))
+		Expect(l.Enabled(logging.DebugLevel)).To(BeTrue())
+	})
+
+	It("distinguishes attribute values", func() {
+		ctx.AddRule(logging.NewConditionRule(logging.DebugLevel, logging.NewAttribute("attr", 1)))
+		Expect(ctx.Logger(logging.NewAttribute("attr", 1)).Enabled(logging.DebugLevel)).To(BeTrue())
+		Expect(ctx.Logger(logging.NewAttribute("attr", "1")).Enabled(logging.DebugLevel)).To(BeFalse())
+		Expect(ctx.Logger(logging.NewAttribute("attr", 2)).Enabled(logging.DebugLevel)).To(BeFalse())
+	})
+
+	It("is invalidated by rule changes", func() {
+		Expect(ctx.Logger(realm).Enabled(logging.DebugLevel)).To(BeFalse())
+		ctx.AddRule(logging.NewConditionRule(logging.DebugLevel, realm))
+		Expect(ctx.Logger(realm).Enabled(logging.DebugLevel)).To(BeTrue())
+		ctx.ResetRules()
+		Expect(ctx.Logger(realm).Enabled(logging.DebugLevel)).To(BeFalse())
+	})
+
+	It("is invalidated by base context changes", func() {
+		nested := logging.NewWithBase(ctx)
+		Expect(nested.Logger(realm).Enabled(logging.DebugLevel)).To(BeFalse())
+		ctx.AddRule(logging.NewConditionRule(logging.DebugLevel, realm))
+		Expect(nested.Logger(realm).Enabled(logging.DebugLevel)).To(BeTrue())
+		ctx.SetDefaultLevel(logging.TraceLevel)
+		Expect(nested.Logger(tag).Enabled(logging.TraceLevel)).To(BeTrue())
+	})
+})
@@ -0,0 +1,241 @@
+/*
+ * Copyright 2023 Mandelsoft. All rights reserved.
+ *  This file is licensed under the Apache Software License, v. 2 except as noted
+ *  otherwise in the LICENSE file
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+package logging_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/go-logr/logr"
+	"github.com/sirupsen/logrus"
+
+	"github.com/mandelsoft/logging"
+	"github.com/mandelsoft/logging/logrusr"
+)
+
+// next provides the location of the line following the caller.
+func next() string {
+	_, file, line, _ := runtime.Caller(1)
+	return fmt.Sprintf("%s:%d", filepath.Base(file), line+1)
+}
+
+func helper(logger logging.Logger, msg string) {
+	logger.WithCallDepth(1).Info(msg)
+}
+
+var _ = Describe("caller reporting", func() {
+	var buf bytes.Buffer
+	var ctx logging.Context
+
+	realm := logging.NewRealm("realm")
+
+	caller := func() string {
+		var data map[string]interface{}
+		ExpectWithOffset(1, json.Unmarshal(buf.Bytes(), &data)).To(Succeed())
+		buf.Reset()
+		return fmt.Sprint(data["caller"])
+	}
+
+	newLogger := func() logr.Logger {
+		log := logrus.New()
+		log.SetLevel(9)
+		log.SetFormatter(&logrus.JSONFormatter{DisableTimestamp: true})
+		log.SetOutput(&buf)
+		return logrusr.New(log, logrusr.WithReportCaller())
+	}
+
+	BeforeEach(func() {
+		buf.Reset()
+		ctx = logging.New(newLogger())
+		ctx.SetDefaultLevel(logging.InfoLevel)
+	})
+
+	It("reports plain logr logger", func() {
+		loc := next()
+		newLogger().Info("test")
+		Expect(caller()).To(Equal(loc))
+	})
+
+	It("reports context logger", func() {
+		logger := ctx.Logger(realm)
+		loc := next()
+		logger.Info("test")
+		Expect(caller()).To(Equal(loc))
+		loc = next()
+		logger.Error("test")
+		Expect(caller()).To(Equal(loc))
+		loc = next()
+		logger.LogError(fmt.Errorf("failed"), "test")
+		Expect(caller()).To(Equal(loc))
+	})
+
+	It("reports default logger", func() {
+		loc := next()
+		ctx.Logger().Warn("test")
+		Expect(caller()).To(Equal(loc))
+	})
+
+	It("reports logger provided by rule", func() {
+		ctx.AddRule(logging.NewConditionRule(logging.DebugLevel, realm))
+		loc := next()
+		ctx.Logger(realm).Debug("test")
+		Expect(caller()).To(Equal(loc))
+	})
+
+	It("reports Log", func() {
+		logger := ctx.Logger(realm)
+		loc := next()
+		logger.Log(logging.InfoLevel, "test")
+		Expect(caller()).To(Equal(loc))
+		loc = next()
+		logger.Log(logging.ErrorLevel, "test")
+		Expect(caller()).To(Equal(loc))
+	})
+
+	It("reports V", func() {
+		loc := next()
+		ctx.V(logging.InfoLevel, realm).Info("test")
+		Expect(caller()).To(Equal(loc))
+		loc = next()
+		ctx.Logger(realm).V(logging.InfoLevel).Error(nil, "test")
+		Expect(caller()).To(Equal(loc))
+	})
+
+	It("reports dynamic logger", func() {
+		logger := logging.DynamicLogger(ctx, realm)
+		loc := next()
+		logger.Info("test")
+		Expect(caller()).To(Equal(loc))
+		loc = next()
+		logger.Log(logging.ErrorLevel, "test")
+		Expect(caller()).To(Equal(loc))
+		loc = next()
+		logger.V(logging.InfoLevel).Info("test")
+		Expect(caller()).To(Equal(loc))
+		loc = next()
+		logger.BoundLogger().Info("test")
+		Expect(caller()).To(Equal(loc))
+		loc = next()
+		logger.WithName("name").Info("test")
+		Expect(caller()).To(Equal(loc))
+	})
+
+	It("reports attribution context logger", func() {
+		actx := logging.NewAttributionContext(ctx, realm)
+		loc := next()
+		actx.Logger().Info("test")
+		Expect(caller()).To(Equal(loc))
+	})
+
+	It("reports plain base logger", func() {
+		ctx.SetBaseLogger(newLogger(), true)
+		loc := next()
+		ctx.Logger(realm).Info("test")
+		Expect(caller()).To(Equal(loc))
+	})
+
+	It("reports sampling and deduplicating loggers", func() {
+		ctx.AddRule(logging.NewSamplingRule(logging.InfoLevel, logging.Sampling{Every: 1}, realm))
+		loc := next()
+		ctx.Logger(realm).Info("test")
+		Expect(caller()).To(Equal(loc))
+
+		ctx.AddRule(logging.NewDeduplicationRule(logging.InfoLevel, time.Minute, realm))
+		loc = next()
+		logging.DynamicLogger(ctx, realm).Info("test")
+		Expect(caller()).To(Equal(loc))
+	})
+
+	It("reports deduplication summaries", func() {
+		ctx.AddRule(logging.NewDeduplicationRule(logging.InfoLevel, time.Minute, realm))
+		logger := ctx.Logger(realm)
+		logger.Info("test")
+		logger.Info("test")
+		buf.Reset()
+		loc := next()
+		logger.Info("other")
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		Expect(lines).To(HaveLen(2))
+		for _, l := range lines {
+			var data map[string]interface{}
+			Expect(json.Unmarshal([]byte(l), &data)).To(Succeed())
+			Expect(data["caller"]).To(Equal(loc))
+		}
+		Expect(lines[0]).To(ContainSubstring(`"repeated":1`))
+	})
+
+	It("reports filtered loggers", func() {
+		ctx.AddFilter(logging.NewLevelFilter(logging.WarnLevel, logging.NewMessageGlob("raised")))
+		ctx.AddRule(logging.NewSamplingRule(logging.InfoLevel, logging.Sampling{Every: 1}, realm))
+		loc := next()
+		ctx.Logger(realm).Info("raised")
+		Expect(caller()).To(Equal(loc))
+		loc = next()
+		logging.DynamicLogger(ctx).Info("test")
+		Expect(caller()).To(Equal(loc))
+
+		old := logging.SetExitHook(func(code int) {})
+		defer logging.SetExitHook(old)
+		loc = next()
+		ctx.Logger(realm).Fatal("test")
+		Expect(caller()).To(Equal(loc))
+	})
+
+	It("reports fatal records", func() {
+		old := logging.SetExitHook(func(code int) {})
+		defer logging.SetExitHook(old)
+
+		loc := next()
+		ctx.Logger(realm).Fatal("test")
+		Expect(caller()).To(Equal(loc))
+		loc = next()
+		logging.DynamicLogger(ctx, realm).Fatal("test")
+		Expect(caller()).To(Equal(loc))
+		loc = next()
+		ctx.LoggerFor(logging.NewRealm("other")).Fatal("test")
+		Expect(caller()).To(Equal(loc))
+	})
+
+	It("reports logr logger based on context sink", func() {
+		l := logging.NewLogr(ctx, nil).WithName("name")
+		loc := next()
+		l.Info("test")
+		Expect(caller()).To(Equal(loc))
+		loc = next()
+		l.Error(nil, "test")
+		Expect(caller()).To(Equal(loc))
+	})
+
+	It("reports caller of helper functions", func() {
+		loc := next()
+		helper(ctx.Logger(realm), "test")
+		Expect(caller()).To(Equal(loc))
+		loc = next()
+		helper(logging.DynamicLogger(ctx, realm), "test")
+		Expect(caller()).To(Equal(loc))
+	})
+})
@@ -0,0 +1,135 @@
+/*
+ * Copyright 2023 Mandelsoft. All rights reserved.
+ *  This file is licensed under the Apache Software License, v. 2 except as noted
+ *  otherwise in the LICENSE file
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package logging_test
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/mandelsoft/logging"
+)
+
+// These tests are intended to be run with the race detector
+// (go test -race).
+var _ = Describe("concurrent access", func() {
+	var ctx logging.Context
+	var nested logging.Context
+
+	realm := logging.NewRealm("realm")
+
+	BeforeEach(func() {
+		ctx = logging.New(logr.Discard())
+		nested = logging.NewWithBase(logging.NewWithBase(ctx))
+	})
+
+	run := func(writers []func(i int), readers []func()) {
+		var wg sync.WaitGroup
+		done := make(chan struct{})
+
+		for _, r := range readers {
+			wg.Add(1)
+			go func(r func()) {
+				defer wg.Done()
+				for {
+					select {
+					case <-done:
+						return
+					default:
+						r()
+					}
+				}
+			}(r)
+		}
+
+		var ww sync.WaitGroup
+		for _, w := range writers {
+			ww.Add(1)
+			go func(w func(i int)) {
+				defer ww.Done()
+				for i := 0; i < 200; i++ {
+					w(i)
+				}
+			}(w)
+		}
+		ww.Wait()
+		close(done)
+		wg.Wait()
+	}
+
+	It("handles concurrent rule and level modifications", func() {
+		dyn := logging.DynamicLogger(nested, realm)
+		run(
+			[]func(i int){
+				func(i int) {
+					ctx.AddRule(logging.NewConditionRule(logging.DebugLevel, logging.NewRealm(fmt.Sprintf("r%d", i))))
+				},
+				func(i int) {
+					nested.AddNamedRule("realm", logging.NewConditionRule(logging.TraceLevel-i%2, realm))
+				},
+				func(i int) {
+					ctx.SetDefaultLevel(logging.InfoLevel + i%2)
+				},
+				func(i int) {
+					nested.RemoveRule("realm")
+				},
+			},
+			[]func(){
+				func() { nested.Logger(realm).Debug("test") },
+				func() { nested.Logger(logging.NewRealm("r1")).Enabled(logging.DebugLevel) },
+				func() { dyn.Enabled(logging.TraceLevel) },
+				func() { nested.GetSink(); nested.GetDefaultLevel() },
+				func() { _ = nested.Explain(realm).String() },
+				func() { nested.Rules() },
+			},
+		)
+
+		ctx.SetDefaultLevel(logging.WarnLevel)
+		Expect(nested.GetDefaultLevel()).To(Equal(logging.WarnLevel))
+		Expect(nested.Logger(logging.NewRealm("r199")).Enabled(logging.DebugLevel)).To(BeTrue())
+		Expect(len(ctx.Rules())).To(Equal(200))
+	})
+
+	It("handles concurrent sink modifications", func() {
+		run(
+			[]func(i int){
+				func(i int) {
+					ctx.SetBaseLogger(logr.Discard())
+				},
+				func(i int) {
+					nested.Tree().GetBaseContext().SetNamedSink("sink", logr.Discard())
+				},
+				func(i int) {
+					nested.AddRule(logging.NewNamedRoutingRule("sink", logging.DebugLevel, realm))
+				},
+			},
+			[]func(){
+				func() { nested.Logger(realm).Info("test") },
+				func() { nested.GetSink().Enabled(0) },
+				func() { nested.GetNamedSink("sink") },
+				func() { nested.Tree().LogWriter() },
+			},
+		)
+		Expect(nested.GetNamedSink("sink")).NotTo(BeNil())
+		Expect(nested.GetSink()).To(BeIdenticalTo(ctx.GetSink()))
+	})
+})
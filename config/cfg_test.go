@@ -21,6 +21,8 @@ package config_test
 import (
 	"bytes"
 	"fmt"
+	"math"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -53,6 +55,10 @@ func init() {
 	config.RegisterValueType("logging.test", &TestType{})
 }
 
+func ptr[T any](v T) *T {
+	return &v
+}
+
 var _ = Describe("externaliized data", func() {
 	reg := config.DefaultRegistry()
 
@@ -99,6 +105,44 @@ tag: test
 			Expect(c.Name()).To(Equal("test"))
 		})
 
+		It("deserializes tag prefix", func() {
+			data := `
+tagprefix: net.http
+`
+			cond, err := reg.CreateCondition([]byte(data))
+			Expect(err).To(Succeed())
+			Expect(cond).To(Equal(logging.NewTagPrefix("net.http")))
+
+			e, err := reg.ExportCondition(cond)
+			Expect(err).To(Succeed())
+			Expect(e).To(Equal(ptr(config.TagPrefix("net.http"))))
+		})
+
+		It("deserializes tag sets", func() {
+			data := `
+anytag: [db, net]
+`
+			cond, err := reg.CreateCondition([]byte(data))
+			Expect(err).To(Succeed())
+			Expect(cond).To(Equal(logging.NewAnyTag("db", "net")))
+			e, err := reg.ExportCondition(cond)
+			Expect(err).To(Succeed())
+			Expect(e).To(Equal(ptr(config.AnyTag("db", "net"))))
+
+			data = `
+alltags: [db, net]
+`
+			cond, err = reg.CreateCondition([]byte(data))
+			Expect(err).To(Succeed())
+			Expect(cond).To(Equal(logging.NewAllTags("db", "net")))
+			e, err = reg.ExportCondition(cond)
+			Expect(err).To(Succeed())
+			Expect(e).To(Equal(ptr(config.AllTags("db", "net"))))
+
+			_, err = reg.CreateCondition([]byte(`anytag: []`))
+			Expect(err).To(MatchError(ContainSubstring("tag names missing")))
+		})
+
 		It("deserializes realm", func() {
 			data := `
 realm:
@@ -123,6 +167,45 @@ realmprefix:
 			Expect(c.Name()).To(Equal("test"))
 		})
 
+		It("deserializes realm pattern", func() {
+			data := `
+realmpattern: github.com/our-org/*/controllers/**
+`
+			cond, err := reg.CreateCondition([]byte(data))
+			Expect(err).To(Succeed())
+			c, ok := cond.(*logging.RealmPattern)
+			Expect(ok).To(BeTrue())
+			Expect(c.Pattern()).To(Equal("github.com/our-org/*/controllers/**"))
+			Expect(c.Match(logging.NewRealm("github.com/our-org/mod/controllers/a/b"))).To(BeTrue())
+
+			e, err := reg.ExportCondition(c)
+			Expect(err).To(Succeed())
+			Expect(e).To(Equal(ptr(config.RealmPattern("github.com/our-org/*/controllers/**"))))
+		})
+
+		It("deserializes realm regex", func() {
+			data := `
+realmregex: .*/webhook
+`
+			cond, err := reg.CreateCondition([]byte(data))
+			Expect(err).To(Succeed())
+			c, ok := cond.(*logging.RealmRegex)
+			Expect(ok).To(BeTrue())
+			Expect(c.Expression()).To(Equal(".*/webhook"))
+			Expect(c.Match(logging.NewRealm("mod/webhook"))).To(BeTrue())
+
+			e, err := reg.ExportCondition(c)
+			Expect(err).To(Succeed())
+			Expect(e).To(Equal(ptr(config.RealmRegex(".*/webhook"))))
+		})
+
+		It("rejects invalid realm patterns", func() {
+			_, err := reg.CreateCondition([]byte(`realmpattern: "a/[b"`))
+			Expect(err).To(MatchError(ContainSubstring(`invalid realm pattern "a/[b"`)))
+			_, err = reg.CreateCondition([]byte(`realmregex: "("`))
+			Expect(err).To(MatchError(ContainSubstring(`invalid realm expression "("`)))
+		})
+
 		It("deserializes and", func() {
 			data := `
 and:
@@ -182,6 +265,136 @@ attribute:
 		})
 	})
 
+	Context("value matchers", func() {
+		It("deserializes glob value", func() {
+			data := `
+attribute:
+  name: tenant
+  value:
+    glob: acme-*
+`
+			cond, err := reg.CreateCondition([]byte(data))
+			Expect(err).To(Succeed())
+			Expect(cond).To(Equal(logging.NewAttribute("tenant", logging.NewGlobMatcher("acme-*"))))
+		})
+
+		It("deserializes range value", func() {
+			v, err := reg.CreateValue([]byte(`range: {min: 100}`))
+			Expect(err).To(Succeed())
+			Expect(v).To(Equal(logging.NewRangeMatcher(100, math.Inf(1))))
+
+			_, err = reg.CreateValue([]byte(`range: {min: 10, max: 1}`))
+			Expect(err).To(MatchError("invalid range: min 10 greater than max 1"))
+		})
+
+		It("rejects invalid regular expressions", func() {
+			_, err := reg.CreateValue([]byte(`regex: "("`))
+			Expect(err).NotTo(Succeed())
+		})
+
+		It("configures context", func() {
+			var buf bytes.Buffer
+
+			ctx := logging.New(buflogr.NewWithBuffer(&buf))
+			data := `
+defaultLevel: Info
+rules:
+  - rule:
+      level: Trace
+      conditions:
+        - attribute:
+            name: tenant
+            value:
+              glob: acme-*
+  - rule:
+      level: Debug
+      conditions:
+        - attribute:
+            name: shard
+            value:
+              value: 5
+  - rule:
+      level: Debug
+      conditions:
+        - attribute:
+            name: id
+            value:
+              equals: 42
+`
+			Expect(reg.ConfigureWithData(ctx, []byte(data))).To(Succeed())
+
+			ctx.Logger(logging.NewAttribute("tenant", "acme-1")).Trace("trace")
+			ctx.Logger(logging.NewAttribute("tenant", "other")).Trace("trace")
+			ctx.Logger(logging.NewAttribute("shard", 5)).Debug("debug")
+			ctx.Logger(logging.NewAttribute("id", "42")).Debug("debug")
+
+			Expect("\n" + buf.String()).To(Equal(`
+V[5] trace tenant acme-1
+V[4] debug shard 5
+V[4] debug id 42
+`))
+		})
+
+		It("exports value matchers", func() {
+			ctx := logging.New(buflogr.NewWithBuffer(&bytes.Buffer{}))
+			re, err := logging.NewRegexMatcher("^a")
+			Expect(err).To(Succeed())
+			ctx.AddRule(logging.NewConditionRule(logging.DebugLevel,
+				logging.NewAttribute("a", logging.NewEqualsMatcher("x")),
+				logging.NewAttribute("b", re),
+				logging.NewAttribute("c", logging.NewGlobMatcher("c*")),
+				logging.NewAttribute("d", logging.NewInMatcher("x", "y")),
+				logging.NewAttribute("e", logging.NewRangeMatcher(math.Inf(-1), 10)),
+				logging.NewAttribute("f", logging.NewPresentMatcher()),
+			))
+
+			cfg, err := config.Export(ctx)
+			Expect(err).To(Succeed())
+			data, err := yaml.Marshal(cfg)
+			Expect(err).To(Succeed())
+			Expect("\n" + string(data)).To(Equal(`
+defaultLevel: Info
+rules:
+- rule:
+    conditions:
+    - attribute:
+        name: a
+        value:
+          equals: x
+    - attribute:
+        name: b
+        value:
+          regex: ^a
+    - attribute:
+        name: c
+        value:
+          glob: c*
+    - attribute:
+        name: d
+        value:
+          in:
+          - x
+          - "y"
+    - attribute:
+        name: e
+        value:
+          range:
+            max: 10
+    - attribute:
+        name: f
+        value:
+          present: {}
+    level: Debug
+`))
+
+			nctx := logging.New(buflogr.NewWithBuffer(&bytes.Buffer{}))
+			Expect(config.ConfigureWithData(nctx, data)).To(Succeed())
+			ncfg, err := config.Export(nctx)
+			Expect(err).To(Succeed())
+			Expect(yaml.Marshal(ncfg)).To(Equal(data))
+		})
+	})
+
 	Context("rules", func() {
 		It("deserializes rule", func() {
 			data := `
@@ -197,6 +410,74 @@ rule:
 			Expect(r.Level()).To(Equal(logging.WarnLevel))
 			Expect(r.Conditions()).To(Equal([]logging.Condition{logging.NewRealm("test")}))
 		})
+
+		It("deserializes expiring rule", func() {
+			data := `
+rule:
+  level: Trace
+  expires: "2023-05-01T10:00:00Z"
+  conditions:
+    - realm: test
+`
+			rule, err := reg.CreateRule([]byte(data))
+			Expect(err).To(Succeed())
+			r, ok := rule.(*logging.ExpiringRule)
+			Expect(ok).To(BeTrue())
+			Expect(r.Expiry()).To(Equal(time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)))
+			Expect(r.Rule().(*logging.ConditionRule).Level()).To(Equal(logging.TraceLevel))
+		})
+
+		It("deserializes temporary rule", func() {
+			data := `
+rule:
+  level: Trace
+  duration: 15m
+  conditions:
+    - realm: test
+`
+			rule, err := reg.CreateRule([]byte(data))
+			Expect(err).To(Succeed())
+			r, ok := rule.(*logging.ExpiringRule)
+			Expect(ok).To(BeTrue())
+			Expect(r.Expiry()).To(BeTemporally("~", time.Now().Add(15*time.Minute), time.Second))
+		})
+
+		It("deserializes sampling rule", func() {
+			data := `
+sampling:
+  level: Debug
+  every: 10
+  conditions:
+    - realmprefix: test
+`
+			rule, err := reg.CreateRule([]byte(data))
+			Expect(err).To(Succeed())
+			r, ok := rule.(*logging.SamplingRule)
+			Expect(ok).To(BeTrue())
+			Expect(r.Level()).To(Equal(logging.DebugLevel))
+			Expect(r.Sampling()).To(Equal(logging.Sampling{Every: 10}))
+			Expect(r.Conditions()).To(Equal([]logging.Condition{logging.NewRealmPrefix("test")}))
+		})
+
+		It("rejects sampling rule without sampling", func() {
+			data := `
+sampling:
+  level: Debug
+`
+			_, err := reg.CreateRule([]byte(data))
+			Expect(err).To(MatchError("sampling count or ratio required"))
+		})
+
+		It("rejects expires and duration", func() {
+			data := `
+rule:
+  level: Trace
+  duration: 15m
+  expires: "2023-05-01T10:00:00Z"
+`
+			_, err := reg.CreateRule([]byte(data))
+			Expect(err).To(MatchError("only one of expires and duration possible"))
+		})
 	})
 
 	Context("configure", func() {
@@ -256,6 +537,69 @@ V[5] debug test testvalue
 		})
 	})
 
+	Context("routing", func() {
+		It("routes to named sink", func() {
+			var buf bytes.Buffer
+			var audit bytes.Buffer
+
+			ctx := logging.New(buflogr.NewWithBuffer(&buf))
+			ctx.SetNamedSink("audit", buflogr.NewWithBuffer(&audit))
+			data := `
+defaultLevel: Warn
+rules:
+- route:
+    sink: audit
+    level: Info
+    conditions:
+    - realm: compliance
+`
+			Expect(reg.ConfigureWithData(ctx, []byte(data))).To(Succeed())
+
+			ctx.Logger().Info("info")
+			ctx.Logger(logging.NewRealm("compliance")).Info("info")
+
+			Expect(buf.String()).To(Equal(""))
+			Expect("\n" + audit.String()).To(Equal(`
+V[3] info realm compliance
+`))
+		})
+	})
+
+	Context("replace", func() {
+		It("replaces configuration", func() {
+			var buf bytes.Buffer
+
+			def := buflogr.NewWithBuffer(&buf)
+
+			ctx := logging.New(def)
+			data := `
+defaultLevel: Warn
+rules:
+- rule:
+    level: Debug
+    conditions:
+    - realm: test
+- rule:
+    level: Trace
+    conditions:
+    - tag: test
+`
+			ctx.AddRule(logging.NewConditionRule(logging.InfoLevel, logging.NewRealm("other")))
+			Expect(reg.ReplaceWithData(ctx, []byte(data))).To(Succeed())
+			Expect(reg.ReplaceWithData(ctx, []byte(data))).To(Succeed())
+			Expect(len(ctx.Rules())).To(Equal(2))
+			Expect(ctx.GetDefaultLevel()).To(Equal(logging.WarnLevel))
+
+			ctx.Logger().Info("info")
+			ctx.Logger(logging.NewRealm("other")).Info("info")
+			ctx.Logger(logging.NewRealm("test")).Debug("debug")
+
+			Expect("\n" + buf.String()).To(Equal(`
+V[4] debug realm test
+`))
+		})
+	})
+
 	Context("config composition", func() {
 		It("composes config", func() {
 			cfg := &config.Config{
@@ -304,4 +648,220 @@ rules:
 			Expect(err).To(Succeed())
 		})
 	})
+
+	Context("filters", func() {
+		It("configures filters", func() {
+			var buf bytes.Buffer
+
+			ctx := logging.New(buflogr.NewWithBuffer(&buf))
+			data := `
+defaultLevel: Info
+filters:
+- drop:
+    conditions:
+    - keyvalue:
+        key: path
+        value:
+          value: /healthz
+- level:
+    level: Warn
+    conditions:
+    - message: "retry*"
+    - levels: [Info]
+`
+			Expect(reg.ConfigureWithData(ctx, []byte(data))).To(Succeed())
+			Expect(len(ctx.Filters())).To(Equal(2))
+
+			ctx.Logger().Info("request", "path", "/healthz")
+			ctx.Logger().Info("request", "path", "/api")
+			ctx.Logger().Info("retrying")
+
+			Expect("\n" + buf.String()).To(Equal(`
+V[3] request path /api
+V[2] retrying
+`))
+		})
+
+		It("replaces filters", func() {
+			ctx := logging.New(buflogr.NewWithBuffer(&bytes.Buffer{}))
+			ctx.AddFilter(logging.NewDropFilter(logging.NewKeyPresent("other")))
+
+			Expect(reg.ReplaceWithData(ctx, []byte(`
+filters:
+- drop:
+    conditions:
+    - key: secret
+`))).To(Succeed())
+			Expect(ctx.Filters()).To(Equal([]logging.Filter{logging.NewDropFilter(logging.NewKeyPresent("secret"))}))
+
+			Expect(reg.ReplaceWithData(ctx, []byte(`defaultLevel: Info`))).To(Succeed())
+			Expect(ctx.Filters()).To(BeEmpty())
+		})
+
+		It("rejects invalid filters", func() {
+			cfg := &config.Config{
+				Filters: []config.Filter{config.DropFilter(config.MessageRegex("("))},
+			}
+			err := config.Configure(logging.NewWithBase(nil), cfg)
+			Expect(err).To(MatchError(ContainSubstring("cannot parse filter 0")))
+		})
+
+		It("exports filters", func() {
+			ctx := logging.New(buflogr.NewWithBuffer(&bytes.Buffer{}))
+			re, err := logging.NewMessageRegex("^retry")
+			Expect(err).To(Succeed())
+			ctx.AddFilter(logging.NewDropFilter(logging.NewKeyValueMatch("path", "/healthz"), logging.NewMessageGlob("request*")))
+			ctx.AddFilter(logging.NewLevelFilter(logging.DebugLevel, re, logging.NewKeyPresent("attempt"), logging.NewLevelMatch(logging.InfoLevel, logging.WarnLevel)))
+
+			cfg, err := config.Export(ctx)
+			Expect(err).To(Succeed())
+			data, err := yaml.Marshal(cfg)
+			Expect(err).To(Succeed())
+			Expect("\n" + string(data)).To(Equal(`
+defaultLevel: Info
+filters:
+- drop:
+    conditions:
+    - keyvalue:
+        key: path
+        value:
+          value: /healthz
+    - message: request*
+- level:
+    conditions:
+    - messageregex: ^retry
+    - key: attempt
+    - levels:
+      - Info
+      - Warn
+    level: Debug
+`))
+
+			nctx := logging.New(buflogr.NewWithBuffer(&bytes.Buffer{}))
+			Expect(config.ConfigureWithData(nctx, data)).To(Succeed())
+			ncfg, err := config.Export(nctx)
+			Expect(err).To(Succeed())
+			Expect(yaml.Marshal(ncfg)).To(Equal(data))
+		})
+	})
+
+	Context("export", func() {
+		It("exports context", func() {
+			ctx := logging.New(buflogr.NewWithBuffer(&bytes.Buffer{}))
+			ctx.SetDefaultLevel(logging.WarnLevel)
+			ctx.AddRule(logging.NewConditionRule(logging.DebugLevel, logging.NewTag("tag"), logging.NewRealmPrefix("prefix")))
+			ctx.AddRule(logging.NewConditionRule(logging.TraceLevel,
+				logging.Or(logging.NewRealm("realm"), logging.Not(logging.NewAttribute("attr", "value")))))
+			ctx.AddRule(logging.NewNamedRoutingRule("sink", -1, logging.NewRealm("routed")))
+			ctx.AddRule(logging.NewSamplingRule(logging.DebugLevel, logging.Sampling{Every: 10}, logging.NewRealm("sampled")))
+			ctx.AddRule(logging.NewDeduplicationRule(logging.InfoLevel, 10*time.Second, logging.NewRealm("dedup")))
+
+			cfg, err := config.Export(ctx)
+			Expect(err).To(Succeed())
+			data, err := yaml.Marshal(cfg)
+			Expect(err).To(Succeed())
+			Expect("\n" + string(data)).To(Equal(`
+defaultLevel: Warn
+rules:
+- rule:
+    conditions:
+    - tag: tag
+    - realmprefix: prefix
+    level: Debug
+- rule:
+    conditions:
+    - or:
+      - realm: realm
+      - not:
+          attribute:
+            name: attr
+            value:
+              value: value
+    level: Trace
+- route:
+    conditions:
+    - realm: routed
+    sink: sink
+- sampling:
+    conditions:
+    - realm: sampled
+    every: 10
+    level: Debug
+- deduplication:
+    conditions:
+    - realm: dedup
+    level: Info
+    window: 10s
+`))
+
+			nctx := logging.New(buflogr.NewWithBuffer(&bytes.Buffer{}))
+			Expect(config.ConfigureWithData(nctx, data)).To(Succeed())
+			ncfg, err := config.Export(nctx)
+			Expect(err).To(Succeed())
+			Expect(yaml.Marshal(ncfg)).To(Equal(data))
+		})
+
+		It("exports default level of nested context only if set locally", func() {
+			base := logging.New(buflogr.NewWithBuffer(&bytes.Buffer{}))
+			base.SetDefaultLevel(logging.WarnLevel)
+			nested := logging.NewWithBase(base)
+			nested.AddRule(logging.NewConditionRule(logging.DebugLevel, logging.NewRealm("realm")))
+
+			cfg, err := config.Export(nested)
+			Expect(err).To(Succeed())
+			Expect(cfg.DefaultLevel).To(Equal(""))
+
+			Expect(config.Replace(nested, cfg)).To(Succeed())
+			base.SetDefaultLevel(logging.ErrorLevel)
+			Expect(nested.GetDefaultLevel()).To(Equal(logging.ErrorLevel))
+
+			nested.SetDefaultLevel(logging.DebugLevel)
+			cfg, err = config.Export(nested)
+			Expect(err).To(Succeed())
+			Expect(cfg.DefaultLevel).To(Equal("Debug"))
+		})
+
+		It("does not export expired rules", func() {
+			ctx := logging.New(buflogr.NewWithBuffer(&bytes.Buffer{}))
+			data := `
+rules:
+- rule:
+    level: Debug
+    expires: "2000-01-01T00:00:00Z"
+    conditions:
+    - realm: expired
+- rule:
+    level: Debug
+    duration: 0s
+    conditions:
+    - realm: none
+`
+			Expect(config.ConfigureWithData(ctx, []byte(data))).To(Succeed())
+			Expect(ctx.Rules()).To(BeEmpty())
+			cfg, err := config.Export(ctx)
+			Expect(err).To(Succeed())
+			Expect(cfg.Rules).To(BeEmpty())
+		})
+
+		It("exports expiring rule", func() {
+			ctx := logging.New(buflogr.NewWithBuffer(&bytes.Buffer{}))
+			expiry := time.Now().Add(time.Hour).Truncate(time.Second)
+			ctx.AddRule(logging.NewExpiringRule(logging.NewConditionRule(logging.DebugLevel, logging.NewRealm("realm")), expiry))
+
+			cfg, err := config.Export(ctx)
+			Expect(err).To(Succeed())
+			Expect(len(cfg.Rules)).To(Equal(1))
+			rule, err := config.DefaultRegistry().CreateRuleFromElement(&cfg.Rules[0])
+			Expect(err).To(Succeed())
+			Expect(rule.(*logging.ExpiringRule).Expiry().Equal(expiry)).To(BeTrue())
+		})
+
+		It("rejects rules without element type", func() {
+			ctx := logging.New(buflogr.NewWithBuffer(&bytes.Buffer{}))
+			ctx.AddRule(logging.NewRoutingRule(ctx.GetSink(), logging.DebugLevel, logging.NewRealm("realm")))
+
+			_, err := config.Export(ctx)
+			Expect(err).To(MatchError("cannot export rule #1: no rule type found for *logging.RoutingRule"))
+		})
+	})
 })
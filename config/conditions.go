@@ -30,8 +30,13 @@ func init() {
 	RegisterCondition("or", OrType{})
 	RegisterCondition("not", &NotType{})
 	RegisterCondition("tag", TagType(""))
+	RegisterCondition("tagprefix", TagPrefixType(""))
+	RegisterCondition("anytag", AnyTagType{})
+	RegisterCondition("alltags", AllTagsType{})
 	RegisterCondition("realm", RealmType(""))
 	RegisterCondition("realmprefix", RealmPrefixType(""))
+	RegisterCondition("realmpattern", RealmPatternType(""))
+	RegisterCondition("realmregex", RealmRegexType(""))
 	RegisterCondition("attribute", &AttributeType{})
 }
 
@@ -58,6 +63,18 @@ func (e AndType) Create(r Registry) (logging.Condition, error) {
 	return logging.And(conditions...), nil
 }
 
+func (e AndType) Export(r Registry, c logging.Condition) (ConditionType, error) {
+	if a, ok := c.(*logging.AndExpr); ok {
+		conditions, err := ExportConditions(r, a.Conditions())
+		if err != nil {
+			return nil, err
+		}
+		s := AndType(conditions)
+		return &s, nil
+	}
+	return nil, nil
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 
 type OrType AndType
@@ -75,6 +92,18 @@ func (e OrType) Create(r Registry) (logging.Condition, error) {
 	return logging.Or(conditions...), nil
 }
 
+func (e OrType) Export(r Registry, c logging.Condition) (ConditionType, error) {
+	if o, ok := c.(*logging.OrExpr); ok {
+		conditions, err := ExportConditions(r, o.Conditions())
+		if err != nil {
+			return nil, err
+		}
+		s := OrType(conditions)
+		return &s, nil
+	}
+	return nil, nil
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 
 type NotType struct {
@@ -94,6 +123,17 @@ func (e *NotType) Create(r Registry) (logging.Condition, error) {
 	return logging.Not(c), nil
 }
 
+func (e *NotType) Export(r Registry, c logging.Condition) (ConditionType, error) {
+	if n, ok := c.(*logging.NotExpr); ok {
+		cond, err := r.ExportCondition(n.Condition())
+		if err != nil {
+			return nil, err
+		}
+		return &NotType{*cond}, nil
+	}
+	return nil, nil
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 
 type TagType string
@@ -110,6 +150,86 @@ func (e TagType) Create(_ Registry) (logging.Condition, error) {
 	return logging.NewTag(string(e)), nil
 }
 
+func (e TagType) Export(_ Registry, c logging.Condition) (ConditionType, error) {
+	if t, ok := c.(logging.Tag); ok {
+		s := TagType(t.Name())
+		return &s, nil
+	}
+	return nil, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+type TagPrefixType string
+
+func TagPrefix(tag string) Condition {
+	s := TagPrefixType(tag)
+	return newCondition("tagprefix", &s)
+}
+
+func (e TagPrefixType) Create(_ Registry) (logging.Condition, error) {
+	if e == "" {
+		return nil, fmt.Errorf("tag name missing")
+	}
+	return logging.NewTagPrefix(string(e)), nil
+}
+
+func (e TagPrefixType) Export(_ Registry, c logging.Condition) (ConditionType, error) {
+	if t, ok := c.(logging.TagPrefix); ok {
+		s := TagPrefixType(t.Name())
+		return &s, nil
+	}
+	return nil, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+type AnyTagType []string
+
+func AnyTag(tags ...string) Condition {
+	s := AnyTagType(tags)
+	return newCondition("anytag", &s)
+}
+
+func (e AnyTagType) Create(_ Registry) (logging.Condition, error) {
+	if len(e) == 0 {
+		return nil, fmt.Errorf("tag names missing")
+	}
+	return logging.NewAnyTag(e...), nil
+}
+
+func (e AnyTagType) Export(_ Registry, c logging.Condition) (ConditionType, error) {
+	if t, ok := c.(*logging.AnyTag); ok {
+		s := AnyTagType(t.Tags())
+		return &s, nil
+	}
+	return nil, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+type AllTagsType []string
+
+func AllTags(tags ...string) Condition {
+	s := AllTagsType(tags)
+	return newCondition("alltags", &s)
+}
+
+func (e AllTagsType) Create(_ Registry) (logging.Condition, error) {
+	if len(e) == 0 {
+		return nil, fmt.Errorf("tag names missing")
+	}
+	return logging.NewAllTags(e...), nil
+}
+
+func (e AllTagsType) Export(_ Registry, c logging.Condition) (ConditionType, error) {
+	if t, ok := c.(*logging.AllTags); ok {
+		s := AllTagsType(t.Tags())
+		return &s, nil
+	}
+	return nil, nil
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 
 type RealmType string
@@ -126,6 +246,14 @@ func (e RealmType) Create(_ Registry) (logging.Condition, error) {
 	return logging.NewRealm(string(e)), nil
 }
 
+func (e RealmType) Export(_ Registry, c logging.Condition) (ConditionType, error) {
+	if t, ok := c.(logging.Realm); ok {
+		s := RealmType(t.Name())
+		return &s, nil
+	}
+	return nil, nil
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 
 type RealmPrefixType string
@@ -142,6 +270,62 @@ func (e RealmPrefixType) Create(_ Registry) (logging.Condition, error) {
 	return logging.NewRealmPrefix(string(e)), nil
 }
 
+func (e RealmPrefixType) Export(_ Registry, c logging.Condition) (ConditionType, error) {
+	if t, ok := c.(logging.RealmPrefix); ok {
+		s := RealmPrefixType(t.Name())
+		return &s, nil
+	}
+	return nil, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+type RealmPatternType string
+
+func RealmPattern(pattern string) Condition {
+	s := RealmPatternType(pattern)
+	return newCondition("realmpattern", &s)
+}
+
+func (e RealmPatternType) Create(_ Registry) (logging.Condition, error) {
+	if e == "" {
+		return nil, fmt.Errorf("realm pattern missing")
+	}
+	return logging.NewRealmPattern(string(e))
+}
+
+func (e RealmPatternType) Export(_ Registry, c logging.Condition) (ConditionType, error) {
+	if p, ok := c.(*logging.RealmPattern); ok {
+		s := RealmPatternType(p.Pattern())
+		return &s, nil
+	}
+	return nil, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+type RealmRegexType string
+
+func RealmRegex(expr string) Condition {
+	s := RealmRegexType(expr)
+	return newCondition("realmregex", &s)
+}
+
+func (e RealmRegexType) Create(_ Registry) (logging.Condition, error) {
+	if e == "" {
+		return nil, fmt.Errorf("realm expression missing")
+	}
+	return logging.NewRealmRegex(string(e))
+}
+
+func (e RealmRegexType) Export(_ Registry, c logging.Condition) (ConditionType, error) {
+	if r, ok := c.(*logging.RealmRegex); ok {
+		s := RealmRegexType(r.Expression())
+		return &s, nil
+	}
+	return nil, nil
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 
 type AttributeType struct {
@@ -173,4 +357,15 @@ func (e *AttributeType) Create(r Registry) (logging.Condition, error) {
 	return logging.NewAttribute(e.Name, v), nil
 }
 
+func (e *AttributeType) Export(r Registry, c logging.Condition) (ConditionType, error) {
+	if a, ok := c.(logging.Attribute); ok {
+		v, err := r.ExportValue(a.Value())
+		if err != nil {
+			return nil, err
+		}
+		return &AttributeType{Name: a.Name(), Value: *v}, nil
+	}
+	return nil, nil
+}
+
 ////////////////////////////////////////////////////////////////////////////////
@@ -25,8 +25,9 @@ import (
 )
 
 type Config struct {
-	DefaultLevel string `json:"defaultLevel,omitempty"`
-	Rules        []Rule `json:"rules,omitempty"`
+	DefaultLevel string   `json:"defaultLevel,omitempty"`
+	Rules        []Rule   `json:"rules,omitempty"`
+	Filters      []Filter `json:"filters,omitempty"`
 }
 
 func (c *Config) UnmarshalFrom(data []byte) error {
@@ -48,3 +49,17 @@ func ConfigureWithData(ctx logging.Context, data []byte) error {
 func Configure(ctx logging.Context, cfg *Config) error {
 	return _registry.Configure(ctx, cfg)
 }
+
+func ReplaceWithData(ctx logging.Context, data []byte) error {
+	return _registry.ReplaceWithData(ctx, data)
+}
+
+func Replace(ctx logging.Context, cfg *Config) error {
+	return _registry.Replace(ctx, cfg)
+}
+
+// Export provides a configuration describing the default level,
+// the rule set and the filters of a logging context.
+func Export(ctx logging.Context) (*Config, error) {
+	return _registry.Export(ctx)
+}
@@ -0,0 +1,294 @@
+/*
+ * Copyright 2023 Mandelsoft. All rights reserved.
+ *  This file is licensed under the Apache Software License, v. 2 except as noted
+ *  otherwise in the LICENSE file
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/mandelsoft/logging"
+	"github.com/mandelsoft/logging/scheme"
+)
+
+func init() {
+	RegisterFilter("drop", &DropFilterType{})
+	RegisterFilter("level", &LevelFilterType{})
+
+	RegisterRecordCondition("message", MessageType(""))
+	RegisterRecordCondition("messageregex", MessageRegexType(""))
+	RegisterRecordCondition("key", KeyType(""))
+	RegisterRecordCondition("keyvalue", &KeyValueType{})
+	RegisterRecordCondition("levels", LevelsType{})
+}
+
+func newFilter(typ string, v FilterType) Filter {
+	return scheme.NewElement(typ, v)
+}
+
+func newRecordCondition(typ string, v RecordConditionType) RecordCondition {
+	return scheme.NewElement(typ, v)
+}
+
+func ParseRecordConditions(r Registry, list []RecordCondition) ([]logging.RecordCondition, error) {
+	conditions := []logging.RecordCondition{}
+	for i := range list {
+		c, err := r.CreateRecordConditionFromElement(&list[i])
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse record condition %d: %w", i, err)
+		}
+		conditions = append(conditions, c)
+	}
+	return conditions, nil
+}
+
+func ExportRecordConditions(r Registry, list []logging.RecordCondition) ([]RecordCondition, error) {
+	conditions := []RecordCondition{}
+	for i, c := range list {
+		e, err := r.ExportRecordCondition(c)
+		if err != nil {
+			return nil, fmt.Errorf("cannot export record condition %d: %w", i, err)
+		}
+		conditions = append(conditions, *e)
+	}
+	return conditions, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// DropFilterType describes a filter dropping all log records
+// matching all given record conditions.
+type DropFilterType struct {
+	Conditions []RecordCondition `json:"conditions"`
+}
+
+func DropFilter(conds ...RecordCondition) Filter {
+	return newFilter("drop", &DropFilterType{Conditions: conds})
+}
+
+func (f *DropFilterType) Create(reg Registry) (logging.Filter, error) {
+	conditions, err := ParseRecordConditions(reg, f.Conditions)
+	if err != nil {
+		return nil, err
+	}
+	return logging.NewDropFilter(conditions...), nil
+}
+
+func (f *DropFilterType) Export(reg Registry, filter logging.Filter) (FilterType, error) {
+	r, ok := filter.(*logging.FilterRule)
+	if !ok || r.Level() != logging.None {
+		return nil, nil
+	}
+	conditions, err := ExportRecordConditions(reg, r.Conditions())
+	if err != nil {
+		return nil, err
+	}
+	return &DropFilterType{Conditions: conditions}, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// LevelFilterType describes a filter changing the level of all log records
+// matching all given record conditions.
+type LevelFilterType struct {
+	Level      string            `json:"level"`
+	Conditions []RecordCondition `json:"conditions"`
+}
+
+func LevelFilter(level string, conds ...RecordCondition) Filter {
+	return newFilter("level", &LevelFilterType{Level: level, Conditions: conds})
+}
+
+func (f *LevelFilterType) Create(reg Registry) (logging.Filter, error) {
+	l, err := logging.ParseLevel(f.Level)
+	if err != nil {
+		return nil, err
+	}
+	conditions, err := ParseRecordConditions(reg, f.Conditions)
+	if err != nil {
+		return nil, err
+	}
+	return logging.NewLevelFilter(l, conditions...), nil
+}
+
+func (f *LevelFilterType) Export(reg Registry, filter logging.Filter) (FilterType, error) {
+	r, ok := filter.(*logging.FilterRule)
+	if !ok || r.Level() == logging.None {
+		return nil, nil
+	}
+	conditions, err := ExportRecordConditions(reg, r.Conditions())
+	if err != nil {
+		return nil, err
+	}
+	return &LevelFilterType{Level: logging.LevelName(r.Level()), Conditions: conditions}, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// MessageType describes a glob pattern for the message of a log record.
+type MessageType string
+
+func Message(pattern string) RecordCondition {
+	s := MessageType(pattern)
+	return newRecordCondition("message", &s)
+}
+
+func (e MessageType) Create(_ Registry) (logging.RecordCondition, error) {
+	if e == "" {
+		return nil, fmt.Errorf("message pattern missing")
+	}
+	return logging.NewMessageGlob(string(e)), nil
+}
+
+func (e MessageType) Export(_ Registry, c logging.RecordCondition) (RecordConditionType, error) {
+	if m, ok := c.(*logging.MessageGlob); ok {
+		s := MessageType(m.Pattern())
+		return &s, nil
+	}
+	return nil, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// MessageRegexType describes a regular expression for the message of a
+// log record.
+type MessageRegexType string
+
+func MessageRegex(expr string) RecordCondition {
+	s := MessageRegexType(expr)
+	return newRecordCondition("messageregex", &s)
+}
+
+func (e MessageRegexType) Create(_ Registry) (logging.RecordCondition, error) {
+	if e == "" {
+		return nil, fmt.Errorf("message expression missing")
+	}
+	return logging.NewMessageRegex(string(e))
+}
+
+func (e MessageRegexType) Export(_ Registry, c logging.RecordCondition) (RecordConditionType, error) {
+	if m, ok := c.(*logging.MessageRegex); ok {
+		s := MessageRegexType(m.Expression())
+		return &s, nil
+	}
+	return nil, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// KeyType describes the presence of a key in the values of a log record.
+type KeyType string
+
+func Key(key string) RecordCondition {
+	s := KeyType(key)
+	return newRecordCondition("key", &s)
+}
+
+func (e KeyType) Create(_ Registry) (logging.RecordCondition, error) {
+	if e == "" {
+		return nil, fmt.Errorf("key name missing")
+	}
+	return logging.NewKeyPresent(string(e)), nil
+}
+
+func (e KeyType) Export(_ Registry, c logging.RecordCondition) (RecordConditionType, error) {
+	if k, ok := c.(logging.KeyPresent); ok {
+		s := KeyType(k.Key())
+		return &s, nil
+	}
+	return nil, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// KeyValueType describes a key with a dedicated value in the values
+// of a log record.
+type KeyValueType struct {
+	Key   string `json:"key"`
+	Value Value  `json:"value,omitempty"`
+}
+
+func KeyValue(key string, value interface{}) RecordCondition {
+	var s Value
+	if v, ok := value.(Value); ok {
+		s = v
+	} else {
+		s = GenericValue(value)
+	}
+	return newRecordCondition("keyvalue", &KeyValueType{
+		Key:   key,
+		Value: s,
+	})
+}
+
+func (e *KeyValueType) Create(r Registry) (logging.RecordCondition, error) {
+	if e.Key == "" {
+		return nil, fmt.Errorf("key name missing")
+	}
+	v, err := r.CreateValueFromElement(&e.Value)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse value: %s", err)
+	}
+	return logging.NewKeyValueMatch(e.Key, v), nil
+}
+
+func (e *KeyValueType) Export(r Registry, c logging.RecordCondition) (RecordConditionType, error) {
+	if k, ok := c.(*logging.KeyValueMatch); ok {
+		v, err := r.ExportValue(k.Value())
+		if err != nil {
+			return nil, err
+		}
+		return &KeyValueType{Key: k.Key(), Value: *v}, nil
+	}
+	return nil, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// LevelsType describes the set of levels a log record must have.
+type LevelsType []string
+
+func Levels(levels ...string) RecordCondition {
+	s := LevelsType(levels)
+	return newRecordCondition("levels", &s)
+}
+
+func (e LevelsType) Create(_ Registry) (logging.RecordCondition, error) {
+	if len(e) == 0 {
+		return nil, fmt.Errorf("levels missing")
+	}
+	levels := make([]int, len(e))
+	for i, n := range e {
+		l, err := logging.ParseLevel(n)
+		if err != nil {
+			return nil, err
+		}
+		levels[i] = l
+	}
+	return logging.NewLevelMatch(levels...), nil
+}
+
+func (e LevelsType) Export(_ Registry, c logging.RecordCondition) (RecordConditionType, error) {
+	if m, ok := c.(*logging.LevelMatch); ok {
+		s := LevelsType{}
+		for _, l := range m.Levels() {
+			s = append(s, logging.LevelName(l))
+		}
+		return &s, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,212 @@
+/*
+ * Copyright 2023 Mandelsoft. All rights reserved.
+ *  This file is licensed under the Apache Software License, v. 2 except as noted
+ *  otherwise in the LICENSE file
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/mandelsoft/logging"
+)
+
+func init() {
+	RegisterValueType("equals", &EqualsType{})
+	RegisterValueType("regex", RegexType(""))
+	RegisterValueType("glob", GlobType(""))
+	RegisterValueType("in", InType{})
+	RegisterValueType("range", &RangeType{})
+	RegisterValueType("present", PresentType{})
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// EqualsType describes a value matcher for values equal to a given one.
+// In contrast to the plain value type, numbers and strings are compared
+// with type coercion (see logging.EqualsMatcher).
+type EqualsType struct {
+	Value interface{}
+}
+
+func Equals(v interface{}) Value {
+	return newValue("equals", &EqualsType{v})
+}
+
+// MarshalJSON returns m as the JSON encoding of m.
+func (m EqualsType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.Value)
+}
+
+// UnmarshalJSON sets *m to a copy of data.
+func (m *EqualsType) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &m.Value)
+}
+
+func (m EqualsType) Create(_ Registry) (interface{}, error) {
+	return logging.NewEqualsMatcher(m.Value), nil
+}
+
+func (m EqualsType) Export(_ Registry, v any) (ValueType, error) {
+	if e, ok := v.(*logging.EqualsMatcher); ok {
+		return &EqualsType{e.Value()}, nil
+	}
+	return nil, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// RegexType describes a value matcher using a regular expression.
+type RegexType string
+
+func Regex(expr string) Value {
+	s := RegexType(expr)
+	return newValue("regex", &s)
+}
+
+func (m RegexType) Create(_ Registry) (interface{}, error) {
+	if m == "" {
+		return nil, fmt.Errorf("regular expression missing")
+	}
+	return logging.NewRegexMatcher(string(m))
+}
+
+func (m RegexType) Export(_ Registry, v any) (ValueType, error) {
+	if e, ok := v.(*logging.RegexMatcher); ok {
+		s := RegexType(e.Expression())
+		return &s, nil
+	}
+	return nil, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// GlobType describes a value matcher using a glob pattern.
+type GlobType string
+
+func Glob(pattern string) Value {
+	s := GlobType(pattern)
+	return newValue("glob", &s)
+}
+
+func (m GlobType) Create(_ Registry) (interface{}, error) {
+	if m == "" {
+		return nil, fmt.Errorf("glob pattern missing")
+	}
+	return logging.NewGlobMatcher(string(m)), nil
+}
+
+func (m GlobType) Export(_ Registry, v any) (ValueType, error) {
+	if e, ok := v.(*logging.GlobMatcher); ok {
+		s := GlobType(e.Pattern())
+		return &s, nil
+	}
+	return nil, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// InType describes a value matcher for a set of values.
+type InType []interface{}
+
+func In(values ...interface{}) Value {
+	s := InType(values)
+	return newValue("in", &s)
+}
+
+func (m InType) Create(_ Registry) (interface{}, error) {
+	if len(m) == 0 {
+		return nil, fmt.Errorf("values missing")
+	}
+	return logging.NewInMatcher(m...), nil
+}
+
+func (m InType) Export(_ Registry, v any) (ValueType, error) {
+	if e, ok := v.(*logging.InMatcher); ok {
+		s := InType(e.Values())
+		return &s, nil
+	}
+	return nil, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// RangeType describes a value matcher for a closed numeric interval.
+// A missing bound describes an open interval.
+type RangeType struct {
+	Min *float64 `json:"min,omitempty"`
+	Max *float64 `json:"max,omitempty"`
+}
+
+// Range provides a range value. Infinite bounds (math.Inf)
+// are omitted.
+func Range(min, max float64) Value {
+	return newValue("range", rangeType(min, max))
+}
+
+func rangeType(min, max float64) *RangeType {
+	s := &RangeType{}
+	if !math.IsInf(min, 0) {
+		s.Min = &min
+	}
+	if !math.IsInf(max, 0) {
+		s.Max = &max
+	}
+	return s
+}
+
+func (m *RangeType) Create(_ Registry) (interface{}, error) {
+	min, max := math.Inf(-1), math.Inf(1)
+	if m.Min != nil {
+		min = *m.Min
+	}
+	if m.Max != nil {
+		max = *m.Max
+	}
+	if min > max {
+		return nil, fmt.Errorf("invalid range: min %v greater than max %v", min, max)
+	}
+	return logging.NewRangeMatcher(min, max), nil
+}
+
+func (m *RangeType) Export(_ Registry, v any) (ValueType, error) {
+	if e, ok := v.(*logging.RangeMatcher); ok {
+		return rangeType(e.Min(), e.Max()), nil
+	}
+	return nil, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// PresentType describes a value matcher matching any value.
+type PresentType struct{}
+
+func Present() Value {
+	return newValue("present", &PresentType{})
+}
+
+func (m PresentType) Create(_ Registry) (interface{}, error) {
+	return logging.NewPresentMatcher(), nil
+}
+
+func (m PresentType) Export(_ Registry, v any) (ValueType, error) {
+	if _, ok := v.(logging.PresentMatcher); ok {
+		return &PresentType{}, nil
+	}
+	return nil, nil
+}
@@ -50,6 +50,13 @@ func (m GenericValueType) Create(r Registry) (interface{}, error) {
 	return m.Value, nil
 }
 
+func (m GenericValueType) Export(_ Registry, v any) (ValueType, error) {
+	if _, ok := v.(logging.ValueMatcher); ok {
+		return nil, nil
+	}
+	return &GenericValueType{v}, nil
+}
+
 func GenericValue(v interface{}) Value {
 	s := GenericValueType{v}
 	return newValue("value", &s)
@@ -58,15 +65,36 @@ func GenericValue(v interface{}) Value {
 type Rule = scheme.Element[RuleType]
 type Condition = scheme.Element[ConditionType]
 type Value = scheme.Element[ValueType]
+type Filter = scheme.Element[FilterType]
+type RecordCondition = scheme.Element[RecordConditionType]
 
 type RuleType = scheme.Factory[logging.Rule, Registry]
 type ConditionType = scheme.Factory[logging.Condition, Registry]
 type ValueType = scheme.Factory[any, Registry]
+type FilterType = scheme.Factory[logging.Filter, Registry]
+type RecordConditionType = scheme.Factory[logging.RecordCondition, Registry]
+
+// Exporter is an optional interface for element types registered
+// at a Registry. It is used to describe an object of the logging library
+// by an element of the registered type (see Registry.Export).
+// If the given object cannot be described by the element type,
+// nil is returned.
+type Exporter[T any] interface {
+	Export(r Registry, o T) (scheme.Factory[T, Registry], error)
+}
+
+type RuleExporter = Exporter[logging.Rule]
+type ConditionExporter = Exporter[logging.Condition]
+type ValueExporter = Exporter[any]
+type FilterExporter = Exporter[logging.Filter]
+type RecordConditionExporter = Exporter[logging.RecordCondition]
 
 type Registry interface {
 	RegisterRuleType(name string, ty RuleType)
 	RegisterConditionType(name string, ty ConditionType)
 	RegisterValueType(name string, ty ValueType)
+	RegisterFilterType(name string, ty FilterType)
+	RegisterRecordConditionType(name string, ty RecordConditionType)
 
 	CreateConditionFromElement(e *Condition) (logging.Condition, error)
 	CreateRuleFromElement(e *Rule) (logging.Rule, error)
@@ -74,6 +102,8 @@ type Registry interface {
 	CreateCondition(data []byte) (logging.Condition, error)
 	CreateRule(data []byte) (logging.Rule, error)
 	CreateValue(data []byte) (any, error)
+	CreateFilterFromElement(e *Filter) (logging.Filter, error)
+	CreateRecordConditionFromElement(e *RecordCondition) (logging.RecordCondition, error)
 
 	Evaluate(cfg *Config) error
 	EvaluateFromData(data []byte) (*Config, error)
@@ -81,13 +111,44 @@ type Registry interface {
 	Configure(ctx logging.Context, cfg *Config) error
 	ConfigureWithData(ctx logging.Context, data []byte) error
 
+	// ExportRule provides a serializable element describing a rule.
+	// It uses the registered rule types implementing RuleExporter.
+	ExportRule(rule logging.Rule) (*Rule, error)
+	// ExportCondition provides a serializable element describing a condition.
+	// It uses the registered condition types implementing ConditionExporter.
+	ExportCondition(cond logging.Condition) (*Condition, error)
+	// ExportValue provides a serializable element describing a value.
+	// It uses the registered value types implementing ValueExporter.
+	ExportValue(v any) (*Value, error)
+	// ExportFilter provides a serializable element describing a filter.
+	// It uses the registered filter types implementing FilterExporter.
+	ExportFilter(f logging.Filter) (*Filter, error)
+	// ExportRecordCondition provides a serializable element describing a
+	// record condition. It uses the registered record condition types
+	// implementing RecordConditionExporter.
+	ExportRecordCondition(c logging.RecordCondition) (*RecordCondition, error)
+	// Export provides a configuration describing the default level,
+	// the rule set and the filters of a logging context.
+	// The default level of a nested context is only included,
+	// if it is set at the context itself.
+	Export(ctx logging.Context) (*Config, error)
+
+	// Replace atomically replaces the default level and the rule set
+	// of a logging context by the configured ones. If no default level
+	// is configured, the initial default level of the context is used.
+	// The filters are replaced by the configured ones, also.
+	Replace(ctx logging.Context, cfg *Config) error
+	ReplaceWithData(ctx logging.Context, data []byte) error
+
 	Copy() Registry
 }
 
 type registry struct {
-	rules      *scheme.Scheme[logging.Rule, Registry]
-	conditions *scheme.Scheme[logging.Condition, Registry]
-	values     *scheme.Scheme[any, Registry]
+	rules            *scheme.Scheme[logging.Rule, Registry]
+	conditions       *scheme.Scheme[logging.Condition, Registry]
+	values           *scheme.Scheme[any, Registry]
+	filters          *scheme.Scheme[logging.Filter, Registry]
+	recordConditions *scheme.Scheme[logging.RecordCondition, Registry]
 }
 
 func NewRegistry() Registry {
@@ -95,6 +156,8 @@ func NewRegistry() Registry {
 	r.rules = scheme.NewScheme[logging.Rule, Registry](r)
 	r.conditions = scheme.NewScheme[logging.Condition, Registry](r)
 	r.values = scheme.NewScheme[interface{}, Registry](r)
+	r.filters = scheme.NewScheme[logging.Filter, Registry](r)
+	r.recordConditions = scheme.NewScheme[logging.RecordCondition, Registry](r)
 	r.RegisterValueType("value", GenericValueType{})
 	return r
 }
@@ -104,6 +167,8 @@ func (r *registry) Copy() Registry {
 	c.rules = r.rules.Copy(c)
 	c.conditions = r.conditions.Copy(c)
 	c.values = r.values.Copy(c)
+	c.filters = r.filters.Copy(c)
+	c.recordConditions = r.recordConditions.Copy(c)
 	return c
 }
 
@@ -119,6 +184,14 @@ func (r *registry) RegisterValueType(name string, ty ValueType) {
 	r.values.Register(name, ty)
 }
 
+func (r *registry) RegisterFilterType(name string, ty FilterType) {
+	r.filters.Register(name, ty)
+}
+
+func (r *registry) RegisterRecordConditionType(name string, ty RecordConditionType) {
+	r.recordConditions.Register(name, ty)
+}
+
 func (r *registry) CreateRuleFromElement(e *Rule) (logging.Rule, error) {
 	return r.rules.GetFromElement(e)
 }
@@ -131,6 +204,14 @@ func (r *registry) CreateValueFromElement(e *Value) (interface{}, error) {
 	return r.values.GetFromElement(e)
 }
 
+func (r *registry) CreateFilterFromElement(e *Filter) (logging.Filter, error) {
+	return r.filters.GetFromElement(e)
+}
+
+func (r *registry) CreateRecordConditionFromElement(e *RecordCondition) (logging.RecordCondition, error) {
+	return r.recordConditions.GetFromElement(e)
+}
+
 func (r *registry) CreateRule(data []byte) (logging.Rule, error) {
 	return r.rules.Get(data)
 }
@@ -172,7 +253,20 @@ func (r *registry) Evaluate(cfg *Config) error {
 			return fmt.Errorf("cannot parse rule %d: %w", i, err)
 		}
 	}
-	return nil
+	_, err := r.parseFilters(cfg.Filters)
+	return err
+}
+
+func (r *registry) parseFilters(list []Filter) ([]logging.Filter, error) {
+	var filters []logging.Filter
+	for i := range list {
+		f, err := r.CreateFilterFromElement(&list[i])
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse filter %d: %w", i, err)
+		}
+		filters = append(filters, f)
+	}
+	return filters, nil
 }
 
 func (r *registry) Configure(ctx logging.Context, cfg *Config) error {
@@ -191,6 +285,12 @@ func (r *registry) Configure(ctx logging.Context, cfg *Config) error {
 		}
 		ctx.AddRule(rule)
 	}
+
+	filters, err := r.parseFilters(cfg.Filters)
+	if err != nil {
+		return err
+	}
+	ctx.AddFilter(filters...)
 	return nil
 }
 
@@ -205,6 +305,105 @@ func (r *registry) ConfigureWithData(ctx logging.Context, data []byte) error {
 	return r.Configure(ctx, &cfg)
 }
 
+func (r *registry) Replace(ctx logging.Context, cfg *Config) error {
+	level := -1
+	if cfg.DefaultLevel != "" {
+		l, err := logging.ParseLevel(cfg.DefaultLevel)
+		if err != nil {
+			return fmt.Errorf("default level: %w", err)
+		}
+		level = l
+	}
+
+	rules := make([]logging.Rule, len(cfg.Rules))
+	for i := range cfg.Rules {
+		rule, err := r.CreateRuleFromElement(&cfg.Rules[i])
+		if err != nil {
+			return fmt.Errorf("cannot parse rule %d: %w", i, err)
+		}
+		rules[i] = rule
+	}
+	filters, err := r.parseFilters(cfg.Filters)
+	if err != nil {
+		return err
+	}
+	ctx.ReplaceConfiguration(level, rules, filters)
+	return nil
+}
+
+func (r *registry) ReplaceWithData(ctx logging.Context, data []byte) error {
+	var cfg Config
+
+	err := cfg.UnmarshalFrom(data)
+	if err != nil {
+		return err
+	}
+
+	return r.Replace(ctx, &cfg)
+}
+
+func (r *registry) ExportRule(rule logging.Rule) (*Rule, error) {
+	return export[logging.Rule](r, r.rules, "rule", rule)
+}
+
+func (r *registry) ExportCondition(cond logging.Condition) (*Condition, error) {
+	return export[logging.Condition](r, r.conditions, "condition", cond)
+}
+
+func (r *registry) ExportValue(v any) (*Value, error) {
+	return export[any](r, r.values, "value", v)
+}
+
+func (r *registry) ExportFilter(f logging.Filter) (*Filter, error) {
+	return export[logging.Filter](r, r.filters, "filter", f)
+}
+
+func (r *registry) ExportRecordCondition(c logging.RecordCondition) (*RecordCondition, error) {
+	return export[logging.RecordCondition](r, r.recordConditions, "record condition", c)
+}
+
+func (r *registry) Export(ctx logging.Context) (*Config, error) {
+	cfg := &Config{}
+	// an inherited default level must not be pinned.
+	if level := ctx.DefaultLevel(); level >= 0 {
+		cfg.DefaultLevel = logging.LevelName(level)
+	}
+
+	// rules are configured in the reverse order of their evaluation.
+	rules := ctx.Rules()
+	for i := len(rules) - 1; i >= 0; i-- {
+		rule, err := r.ExportRule(rules[i].Rule)
+		if err != nil {
+			return nil, fmt.Errorf("cannot export rule %s: %w", rules[i].Id, err)
+		}
+		cfg.Rules = append(cfg.Rules, *rule)
+	}
+	for i, f := range ctx.Filters() {
+		filter, err := r.ExportFilter(f)
+		if err != nil {
+			return nil, fmt.Errorf("cannot export filter %d: %w", i, err)
+		}
+		cfg.Filters = append(cfg.Filters, *filter)
+	}
+	return cfg, nil
+}
+
+func export[T any](r Registry, s *scheme.Scheme[T, Registry], kind string, o T) (*scheme.Element[scheme.Factory[T, Registry]], error) {
+	for _, n := range s.Types() {
+		if e, ok := s.Prototype(n).(Exporter[T]); ok {
+			f, err := e.Export(r, o)
+			if err != nil {
+				return nil, err
+			}
+			if f != nil {
+				elem := scheme.NewElement(n, f)
+				return &elem, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("no %s type found for %T", kind, o)
+}
+
 func ParseConditions(r Registry, list []Condition) ([]logging.Condition, error) {
 	conditions := []logging.Condition{}
 	for i := range list {
@@ -217,6 +416,18 @@ func ParseConditions(r Registry, list []Condition) ([]logging.Condition, error)
 	return conditions, nil
 }
 
+func ExportConditions(r Registry, list []logging.Condition) ([]Condition, error) {
+	conditions := []Condition{}
+	for i, c := range list {
+		e, err := r.ExportCondition(c)
+		if err != nil {
+			return nil, fmt.Errorf("cannot export condition %d: %w", i, err)
+		}
+		conditions = append(conditions, *e)
+	}
+	return conditions, nil
+}
+
 var _registry = NewRegistry()
 
 func DefaultRegistry() Registry {
@@ -234,3 +445,11 @@ func RegisterCondition(name string, ty ConditionType) {
 func RegisterValueType(name string, ty ValueType) {
 	_registry.RegisterValueType(name, ty)
 }
+
+func RegisterFilter(name string, ty FilterType) {
+	_registry.RegisterFilterType(name, ty)
+}
+
+func RegisterRecordCondition(name string, ty RecordConditionType) {
+	_registry.RegisterRecordConditionType(name, ty)
+}
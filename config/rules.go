@@ -19,12 +19,18 @@
 package config
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/mandelsoft/logging"
 	"github.com/mandelsoft/logging/scheme"
 )
 
 func init() {
 	RegisterRule("rule", &ConditionalRuleType{})
+	RegisterRule("route", &RoutingRuleType{})
+	RegisterRule("sampling", &SamplingRuleType{})
+	RegisterRule("deduplication", &DeduplicationRuleType{})
 }
 
 func newRule(typ string, v RuleType) Rule {
@@ -34,10 +40,15 @@ func newRule(typ string, v RuleType) Rule {
 type ConditionalRuleType struct {
 	Level      string      `json:"level"`
 	Conditions []Condition `json:"conditions"`
+	// Expires optionally describes the expiry time of the rule (RFC3339).
+	Expires string `json:"expires,omitempty"`
+	// Duration optionally describes the time the rule is active
+	// after its creation, for example 15m.
+	Duration string `json:"duration,omitempty"`
 }
 
 func ConditionalRule(level string, conds ...Condition) Rule {
-	return newRule("rule", &ConditionalRuleType{level, conds})
+	return newRule("rule", &ConditionalRuleType{Level: level, Conditions: conds})
 }
 
 func (r *ConditionalRuleType) Create(reg Registry) (logging.Rule, error) {
@@ -49,5 +60,190 @@ func (r *ConditionalRuleType) Create(reg Registry) (logging.Rule, error) {
 	if err != nil {
 		return nil, err
 	}
-	return logging.NewConditionRule(l, conditions...), nil
+	rule := logging.NewConditionRule(l, conditions...)
+	return expiringRule(rule, r.Expires, r.Duration)
+}
+
+func (r *ConditionalRuleType) Export(reg Registry, rule logging.Rule) (RuleType, error) {
+	expires := ""
+	if e, ok := rule.(*logging.ExpiringRule); ok {
+		rule = e.Rule()
+		expires = e.Expiry().Format(time.RFC3339)
+	}
+	c, ok := rule.(*logging.ConditionRule)
+	if !ok {
+		return nil, nil
+	}
+	conditions, err := ExportConditions(reg, c.Conditions())
+	if err != nil {
+		return nil, err
+	}
+	return &ConditionalRuleType{Level: logging.LevelName(c.Level()), Conditions: conditions, Expires: expires}, nil
+}
+
+func expiringRule(rule logging.Rule, expires, duration string) (logging.Rule, error) {
+	switch {
+	case expires != "" && duration != "":
+		return nil, fmt.Errorf("only one of expires and duration possible")
+	case expires != "":
+		t, err := time.Parse(time.RFC3339, expires)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expiry time: %w", err)
+		}
+		return logging.NewExpiringRule(rule, t), nil
+	case duration != "":
+		d, err := time.ParseDuration(duration)
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration: %w", err)
+		}
+		return logging.NewTemporaryRule(rule, d), nil
+	default:
+		return rule, nil
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// RoutingRuleType describes a rule routing log messages to a sink registered
+// by name at the configured logging context. If no level is given, the default
+// level of the context is used.
+type RoutingRuleType struct {
+	Sink       string      `json:"sink"`
+	Level      string      `json:"level,omitempty"`
+	Conditions []Condition `json:"conditions"`
+}
+
+func RoutingRule(sink string, level string, conds ...Condition) Rule {
+	return newRule("route", &RoutingRuleType{Sink: sink, Level: level, Conditions: conds})
+}
+
+func (r *RoutingRuleType) Create(reg Registry) (logging.Rule, error) {
+	if r.Sink == "" {
+		return nil, fmt.Errorf("sink name missing")
+	}
+	l := -1
+	if r.Level != "" {
+		level, err := logging.ParseLevel(r.Level)
+		if err != nil {
+			return nil, err
+		}
+		l = level
+	}
+	conditions, err := ParseConditions(reg, r.Conditions)
+	if err != nil {
+		return nil, err
+	}
+	return logging.NewNamedRoutingRule(r.Sink, l, conditions...), nil
+}
+
+func (r *RoutingRuleType) Export(reg Registry, rule logging.Rule) (RuleType, error) {
+	// only routing rules using named sinks can be described.
+	rr, ok := rule.(*logging.RoutingRule)
+	if !ok || rr.SinkName() == "" {
+		return nil, nil
+	}
+	conditions, err := ExportConditions(reg, rr.Conditions())
+	if err != nil {
+		return nil, err
+	}
+	level := ""
+	if rr.Level() >= 0 {
+		level = logging.LevelName(rr.Level())
+	}
+	return &RoutingRuleType{Sink: rr.SinkName(), Level: level, Conditions: conditions}, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// SamplingRuleType describes a rule enabling a log level for a message context,
+// but emitting only one of every n messages (field every) or messages with a
+// given probability (field ratio). Error messages are only sampled, if
+// the field errors is set to true.
+type SamplingRuleType struct {
+	Level      string      `json:"level"`
+	Conditions []Condition `json:"conditions"`
+	Every      int         `json:"every,omitempty"`
+	Ratio      float64     `json:"ratio,omitempty"`
+	Errors     bool        `json:"errors,omitempty"`
+}
+
+func SamplingRule(level string, every int, ratio float64, conds ...Condition) Rule {
+	return newRule("sampling", &SamplingRuleType{Level: level, Conditions: conds, Every: every, Ratio: ratio})
+}
+
+func (r *SamplingRuleType) Create(reg Registry) (logging.Rule, error) {
+	l, err := logging.ParseLevel(r.Level)
+	if err != nil {
+		return nil, err
+	}
+	if r.Every < 0 {
+		return nil, fmt.Errorf("invalid sampling count %d", r.Every)
+	}
+	if r.Ratio < 0 || r.Ratio > 1 {
+		return nil, fmt.Errorf("invalid sampling ratio %g", r.Ratio)
+	}
+	if r.Every == 0 && r.Ratio == 0 {
+		return nil, fmt.Errorf("sampling count or ratio required")
+	}
+	conditions, err := ParseConditions(reg, r.Conditions)
+	if err != nil {
+		return nil, err
+	}
+	return logging.NewSamplingRule(l, logging.Sampling{Every: r.Every, Ratio: r.Ratio, Errors: r.Errors}, conditions...), nil
+}
+
+func (r *SamplingRuleType) Export(reg Registry, rule logging.Rule) (RuleType, error) {
+	sr, ok := rule.(*logging.SamplingRule)
+	if !ok {
+		return nil, nil
+	}
+	conditions, err := ExportConditions(reg, sr.Conditions())
+	if err != nil {
+		return nil, err
+	}
+	s := sr.Sampling()
+	return &SamplingRuleType{Level: logging.LevelName(sr.Level()), Conditions: conditions, Every: s.Every, Ratio: s.Ratio, Errors: s.Errors}, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// DeduplicationRuleType describes a rule enabling a log level for a message
+// context, which suppresses identical consecutive log records issued within
+// a time window (for example 10s).
+type DeduplicationRuleType struct {
+	Level      string      `json:"level"`
+	Conditions []Condition `json:"conditions"`
+	Window     string      `json:"window"`
+}
+
+func DeduplicationRule(level string, window string, conds ...Condition) Rule {
+	return newRule("deduplication", &DeduplicationRuleType{Level: level, Conditions: conds, Window: window})
+}
+
+func (r *DeduplicationRuleType) Create(reg Registry) (logging.Rule, error) {
+	l, err := logging.ParseLevel(r.Level)
+	if err != nil {
+		return nil, err
+	}
+	w, err := time.ParseDuration(r.Window)
+	if err != nil {
+		return nil, fmt.Errorf("invalid window: %w", err)
+	}
+	conditions, err := ParseConditions(reg, r.Conditions)
+	if err != nil {
+		return nil, err
+	}
+	return logging.NewDeduplicationRule(l, w, conditions...), nil
+}
+
+func (r *DeduplicationRuleType) Export(reg Registry, rule logging.Rule) (RuleType, error) {
+	dr, ok := rule.(*logging.DeduplicationRule)
+	if !ok {
+		return nil, nil
+	}
+	conditions, err := ExportConditions(reg, dr.Conditions())
+	if err != nil {
+		return nil, err
+	}
+	return &DeduplicationRuleType{Level: logging.LevelName(dr.Level()), Conditions: conditions, Window: dr.Window().String()}, nil
 }
@@ -19,8 +19,11 @@
 package logging
 
 import (
+	"fmt"
 	"io"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/mandelsoft/logging/logrusl/adapter"
@@ -30,22 +33,121 @@ import (
 
 type context struct {
 	id      ContextId
-	lock    sync.RWMutex
 	base    Context
 	updater *Updater
-	level   int
+
+	// lock serializes modifications of the context state.
+	lock sync.Mutex
+	// state is the actual immutable configuration snapshot.
+	// It is replaced on every modification (copy-on-write),
+	// so readers never block.
+	state atomic.Pointer[state]
+
+	ruleSeq int64
+	// timer is used to handle the next activation change of temporary rules
+	timer *time.Timer
+	// cache holds the evaluation results for the actual watermark
+	cache atomic.Pointer[evaluationCache]
+
+	// watchers are called for configuration changes.
+	watchers watchers
+	// pending are the change events recorded during
+	// a modification to be dispatched after releasing the lock.
+	pending []ChangeEvent
+
+	defaultLogger Logger
+
+	messageContext []MessageContext
+}
+
+// state is an immutable snapshot of the configuration
+// of a logging context.
+type state struct {
+	level int
 
 	// optional information for final log sink stream
 	writer io.Writer
 
 	sink  logr.LogSink
-	rules []Rule
+	sinks map[string]logr.LogSink
+	rules []RuleEntry
+	index *ruleIndex
 
-	defaultLogger Logger
+	filters []Filter
 
-	messageContext []MessageContext
-	effLevel       int
-	effSink        logr.LogSink
+	// seen is the watermark of the base context the effective
+	// settings have been determined for.
+	seen       int64
+	effLevel   int
+	effSink    logr.LogSink
+	effFilters []Filter
+}
+
+// copy provides a private copy of the state, which
+// can be modified before it is published.
+func (s *state) copy() *state {
+	n := *s
+	n.rules = sliceCopy(s.rules)
+	return &n
+}
+
+func (s *state) setBaseLogger(logger logr.Logger, writer io.Writer, plain ...bool) {
+	if len(plain) == 0 || !plain[0] {
+		s.sink = shifted(logger)
+	} else {
+		s.sink = logger.GetSink()
+	}
+	s.writer = writer
+	if s.writer == nil {
+		s.writer = logwriter.DetermineLogWriter(s.sink)
+	}
+}
+
+func (s *state) removeRule(id string) bool {
+	for i, e := range s.rules {
+		if e.Id == id {
+			s.rules = append(s.rules[:i:i], s.rules[i+1:]...)
+			s.index = nil
+			return true
+		}
+	}
+	return false
+}
+
+// expired checks whether a temporary rule is already expired.
+func expired(rule Rule) bool {
+	t, ok := rule.(TemporaryRule)
+	return ok && t.Expired(time.Now())
+}
+
+// appendId appends the id of an added rule, if it has been added.
+func appendId(ids []string, id string) []string {
+	if id == "" {
+		return ids
+	}
+	return append(ids, id)
+}
+
+func (s *state) hasRule(id string) bool {
+	for _, e := range s.rules {
+		if e.Id == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *state) ruleIds() []string {
+	ids := make([]string, len(s.rules))
+	for i, e := range s.rules {
+		ids[i] = e.Id
+	}
+	return ids
+}
+
+func (s *state) resetRules() {
+	s.rules = nil
+	s.index = nil
 }
 
 var _ Context = (*context)(nil)
@@ -69,38 +171,43 @@ func NewWithBaseAndTechnicalSink(base Context, writer io.Writer, baselogger ...l
 
 func newWithBase(base Context, writer io.Writer, baselogger ...logr.Logger) *context {
 	ctx := &context{
-		base:   base,
+		base: base,
+		id:   getId(),
+	}
+	s := &state{
 		level:  -1,
-		id:     getId(),
 		writer: writer,
 	}
 
 	if base == nil {
-		ctx.level = InfoLevel
+		s.level = InfoLevel
 		ctx.updater = NewUpdater(nil)
 	} else {
 		internal := base.Tree()
 		ctx.updater = NewUpdater(internal.Updater())
 		ctx.messageContext = internal.GetMessageContext()
+		s.seen = ctx.updater.SeenWatermark()
 	}
 
 	if len(baselogger) > 0 {
-		ctx.setBaseLogger(baselogger[0], writer)
+		s.setBaseLogger(baselogger[0], writer)
 	}
 	if base == nil && len(baselogger) == 0 {
 		l := adapter.NewLogger()
 		l.Formatter = adapter.NewTextFmtFormatter()
-		ctx.setBaseLogger(logrusr.New(l), writer)
-		ctx.writer = l.Out
+		s.setBaseLogger(logrusr.New(l), writer)
+		s.writer = l.Out
 	}
-	if ctx.writer == nil && ctx.sink == nil {
-		ctx.writer, _ = logrusr.LogWriter(ctx.GetSink())
-		if ctx.writer == nil && ctx.base != nil {
-			ctx.writer = ctx.base.Tree().LogWriter()
+	ctx.determine(s)
+	if s.writer == nil && s.sink == nil {
+		s.writer, _ = logrusr.LogWriter(s.effSink)
+		if s.writer == nil && base != nil {
+			s.writer = base.Tree().LogWriter()
 		}
 	}
+	s.index = newRuleIndex(s.rules)
+	ctx.state.Store(s)
 	ctx.defaultLogger = NewLogger(DynSink(ctx.GetDefaultLevel, 0, ctx.GetSink))
-	ctx._update()
 	return ctx
 }
 
@@ -109,7 +216,7 @@ func (c *context) Tree() ContextSupport {
 }
 
 func (c *context) LogWriter() io.Writer {
-	return c.writer
+	return c.state.Load().writer
 }
 
 func (c *context) GetMessageContext() []MessageContext {
@@ -120,25 +227,67 @@ func (c *context) Updater() *Updater {
 	return c.updater
 }
 
-func (c *context) update() {
-	if !c.updater.Require() {
-		return
+// current provides the actual state. If the base context
+// has been modified since the effective settings of the actual
+// state have been determined, a refreshed state is published.
+func (c *context) current() *state {
+	for {
+		s := c.state.Load()
+		if c.base == nil {
+			return s
+		}
+		c.updater.Require()
+		seen := c.updater.SeenWatermark()
+		if s.seen >= seen {
+			return s
+		}
+		n := *s
+		n.seen = seen
+		c.determine(&n)
+		if c.state.CompareAndSwap(s, &n) {
+			return &n
+		}
 	}
-	c._update()
 }
 
-func (c *context) _update() {
-	if c.level < 0 {
-		c.effLevel = c.base.GetDefaultLevel()
+// determine determines the effective settings for a state.
+func (c *context) determine(s *state) {
+	if s.level < 0 {
+		s.effLevel = c.base.GetDefaultLevel()
 	} else {
-		c.effLevel = c.level
+		s.effLevel = s.level
 	}
 
-	if c.sink == nil {
-		c.effSink = c.base.GetSink()
+	if s.sink == nil {
+		s.effSink = c.base.GetSink()
 	} else {
-		c.effSink = c.sink
+		s.effSink = s.sink
 	}
+
+	s.effFilters = s.filters
+	if c.base != nil {
+		s.effFilters = sliceAppend(s.filters, c.base.Tree().GetFilters()...)
+	}
+}
+
+// modify provides a private copy of the actual state
+// to be modified and published by publish.
+// The effective settings are determined again by publish,
+// so a refresh is not required here.
+// It must be called with the lock held.
+func (c *context) modify() *state {
+	return c.state.Load().copy()
+}
+
+// publish publishes a modified state.
+// It must be called with the lock held.
+func (c *context) publish(s *state) {
+	c.determine(s)
+	if s.index == nil {
+		s.index = newRuleIndex(s.rules)
+	}
+	c.state.Store(s)
+	c.updater.Modify()
 }
 
 func (c *context) LoggingContext() Context {
@@ -150,17 +299,10 @@ func (c *context) AttributionContext() AttributionContext {
 }
 
 func (c *context) GetSink() logr.LogSink {
-	c.lock.Lock()
-	defer c.lock.Unlock()
-
-	c.update()
-	return c.effSink
+	return c.current().effSink
 }
 
 func (c *context) GetBaseContext() Context {
-	c.lock.RLock()
-	defer c.lock.RUnlock()
-
 	return c.base
 }
 
@@ -169,23 +311,21 @@ func (c *context) GetDefaultLogger() Logger {
 }
 
 func (c *context) GetDefaultLevel() int {
-	c.lock.Lock()
-	defer c.lock.Unlock()
+	return c.current().effLevel
+}
 
-	c.update()
-	return c.effLevel
+func (c *context) DefaultLevel() int {
+	return c.state.Load().level
 }
 
 func (c *context) SetDefaultLevel(level int) {
 	c.lock.Lock()
-	defer c.lock.Unlock()
-	c.setDefaultLevel(level)
-	c.updater.Modify()
-	c._update()
-}
+	defer c.unlock()
 
-func (c *context) setDefaultLevel(level int) {
-	c.level = level
+	s := c.modify()
+	s.level = level
+	c.publish(s)
+	c.changed(ChangeDefaultLevel, func(e *ChangeEvent) { e.Level = level })
 }
 
 func (c *context) SetBaseLogger(logger logr.Logger, plain ...bool) {
@@ -194,65 +334,339 @@ func (c *context) SetBaseLogger(logger logr.Logger, plain ...bool) {
 
 func (c *context) SetBaseLoggerWithTechnicalSink(logger logr.Logger, writer io.Writer, plain ...bool) {
 	c.lock.Lock()
-	defer c.lock.Unlock()
+	defer c.unlock()
 
-	c.setBaseLogger(logger, writer, plain...)
-	c.updater.Modify()
-	c._update()
+	s := c.modify()
+	s.setBaseLogger(logger, writer, plain...)
+	c.publish(s)
+	c.changed(ChangeBaseLogger)
 }
 
-func (c *context) setBaseLogger(logger logr.Logger, writer io.Writer, plain ...bool) {
+func (c *context) SetNamedSink(name string, logger logr.Logger, plain ...bool) {
+	var sink logr.LogSink
+
 	if len(plain) == 0 || !plain[0] {
-		c.sink = shifted(logger)
+		sink = shifted(logger)
 	} else {
-		c.sink = logger.GetSink()
+		sink = logger.GetSink()
+	}
+
+	c.lock.Lock()
+	defer c.unlock()
+
+	s := c.modify()
+	sinks := map[string]logr.LogSink{}
+	for n, e := range s.sinks {
+		sinks[n] = e
 	}
-	c.writer = writer
-	if c.writer == nil {
-		c.writer = logwriter.DetermineLogWriter(c.sink)
+	sinks[name] = sink
+	s.sinks = sinks
+	c.publish(s)
+	c.changed(ChangeNamedSink, func(e *ChangeEvent) { e.SinkName = name })
+}
+
+func (c *context) GetNamedSink(name string) logr.LogSink {
+	s := c.state.Load().sinks[name]
+	if s == nil && c.base != nil {
+		return c.base.GetNamedSink(name)
 	}
+	return s
 }
 
 func (c *context) AddRule(rules ...Rule) {
 	c.lock.Lock()
-	defer c.lock.Unlock()
+	defer c.unlock()
 
+	var added, removed []string
+	s := c.modify()
 	for _, rule := range rules {
 		if rule != nil {
-			if upd, ok := rule.(UpdatableRule); ok {
-				i := 0
-				for i < len(c.rules) {
-					f := c.rules[i]
-					if upd.MatchRule(f) {
-						c.rules = append(c.rules[:i], c.rules[i+1:]...)
-					} else {
-						i++
-					}
-				}
+			id, superseded := c.addRule(s, "", rule)
+			added = appendId(added, id)
+			removed = append(removed, superseded...)
+		}
+	}
+	c.publish(s)
+	c.scheduleRuleChange(s)
+	c.rulesChanged(ChangeRuleRemoved, removed)
+	c.rulesChanged(ChangeRuleAdded, added)
+}
+
+func (c *context) AddNamedRule(id string, rule Rule) {
+	if rule == nil {
+		return
+	}
+	c.lock.Lock()
+	defer c.unlock()
+
+	s := c.modify()
+	id, removed := c.addRule(s, id, rule)
+	c.publish(s)
+	c.scheduleRuleChange(s)
+	c.rulesChanged(ChangeRuleRemoved, removed)
+	c.rulesChanged(ChangeRuleAdded, appendId(nil, id))
+}
+
+// addRule adds a rule to a state. It returns the id of the
+// added rule and the ids of the removed (replaced or superseded) rules.
+// Temporary rules already expired are not added (empty id), but
+// still replace a rule with the same name.
+func (c *context) addRule(s *state, id string, rule Rule) (string, []string) {
+	var removed []string
+
+	named := id != ""
+	if expired(rule) {
+		if named && s.removeRule(id) {
+			removed = append(removed, id)
+		}
+		return "", removed
+	}
+	if named {
+		if s.removeRule(id) {
+			removed = append(removed, id)
+		}
+	} else {
+		// generated ids must not collide with rule names.
+		for {
+			c.ruleSeq++
+			id = fmt.Sprintf("#%d", c.ruleSeq)
+			if !s.hasRule(id) {
+				break
 			}
-			c.rules = append(append(c.rules[:0:0], rule), c.rules...)
 		}
 	}
-	c.updater.Modify()
+	if upd, ok := rule.(UpdatableRule); ok {
+		i := 0
+		for i < len(s.rules) {
+			f := s.rules[i]
+			if upd.MatchRule(f.Rule) {
+				removed = append(removed, f.Id)
+				s.rules = append(s.rules[:i], s.rules[i+1:]...)
+			} else {
+				i++
+			}
+		}
+	}
+	s.rules = append(append(s.rules[:0:0], RuleEntry{Id: id, Rule: rule, named: named}), s.rules...)
+	s.index = nil
+	return id, removed
+}
+
+func (c *context) Rules() []RuleEntry {
+	return sliceCopy(c.state.Load().rules)
+}
+
+func (c *context) RemoveRule(id string) bool {
+	c.lock.Lock()
+	defer c.unlock()
+
+	s := c.modify()
+	if !s.removeRule(id) {
+		return false
+	}
+	c.publish(s)
+	c.scheduleRuleChange(s)
+	c.rulesChanged(ChangeRuleRemoved, []string{id})
+	return true
+}
+
+func (c *context) ReplaceRule(id string, rule Rule) bool {
+	if rule == nil || expired(rule) {
+		return c.RemoveRule(id)
+	}
+	c.lock.Lock()
+	defer c.unlock()
+
+	s := c.modify()
+	for i, e := range s.rules {
+		if e.Id == id {
+			s.rules[i].Rule = rule
+			s.index = nil
+			c.publish(s)
+			c.scheduleRuleChange(s)
+			c.rulesChanged(ChangeRuleRemoved, []string{id})
+			c.rulesChanged(ChangeRuleAdded, []string{id})
+			return true
+		}
+	}
+	return false
 }
 
 func (c *context) AddRulesTo(ctx Context) {
-	c.lock.RLock()
-	defer c.lock.RUnlock()
+	rules := c.state.Load().rules
+	if t, ok := ctx.(*context); ok {
+		t.addEntries(rules)
+		return
+	}
+	for i := len(rules) - 1; i >= 0; i-- {
+		e := rules[i]
+		if e.named {
+			ctx.AddNamedRule(e.Id, e.Rule)
+		} else {
+			ctx.AddRule(e.Rule)
+		}
+	}
+}
 
-	rules := make([]Rule, len(c.rules))
-	for i := range rules {
-		rules[i] = c.rules[len(c.rules)-i-1]
+// addEntries adds the rules of a rule set (given in evaluation order)
+// with a single modification keeping the names of named rules.
+func (c *context) addEntries(rules []RuleEntry) {
+	c.lock.Lock()
+	defer c.unlock()
+
+	var added, removed []string
+	s := c.modify()
+	for i := len(rules) - 1; i >= 0; i-- {
+		e := rules[i]
+		id := ""
+		if e.named {
+			id = e.Id
+		}
+		id, superseded := c.addRule(s, id, e.Rule)
+		added = appendId(added, id)
+		removed = append(removed, superseded...)
 	}
-	ctx.AddRule(rules...)
+	c.publish(s)
+	c.scheduleRuleChange(s)
+	c.rulesChanged(ChangeRuleRemoved, removed)
+	c.rulesChanged(ChangeRuleAdded, added)
+}
+
+func (c *context) AddFilter(filters ...Filter) {
+	if len(filters) == 0 {
+		return
+	}
+	c.lock.Lock()
+	defer c.unlock()
+
+	s := c.modify()
+	s.filters = sliceAppend(s.filters, filters...)
+	c.publish(s)
+	c.changed(ChangeFilters)
+}
+
+func (c *context) SetFilters(filters ...Filter) {
+	c.lock.Lock()
+	defer c.unlock()
+
+	s := c.modify()
+	s.filters = sliceCopy(filters)
+	c.publish(s)
+	c.changed(ChangeFilters)
+}
+
+func (c *context) Filters() []Filter {
+	return sliceCopy(c.state.Load().filters)
+}
+
+func (c *context) GetFilters() []Filter {
+	return c.current().effFilters
 }
 
 func (c *context) ResetRules() {
 	c.lock.Lock()
-	defer c.lock.Unlock()
+	defer c.unlock()
 
-	c.rules = nil
-	c.updater.Modify()
+	s := c.modify()
+	removed := s.ruleIds()
+	s.resetRules()
+	c.publish(s)
+	c.scheduleRuleChange(s)
+	c.rulesChanged(ChangeRuleRemoved, removed)
+}
+
+func (c *context) ReplaceRules(level int, rules ...Rule) {
+	c.replace(level, rules, nil, false)
+}
+
+func (c *context) ReplaceConfiguration(level int, rules []Rule, filters []Filter) {
+	c.replace(level, rules, filters, true)
+}
+
+// replace replaces the default level, the rule set and optionally the
+// filters with a single publication of the new state.
+func (c *context) replace(level int, rules []Rule, filters []Filter, withFilters bool) {
+	c.lock.Lock()
+	defer c.unlock()
+
+	if level < 0 && c.base == nil {
+		level = InfoLevel
+	}
+	var added []string
+	s := c.modify()
+	removed := s.ruleIds()
+	old := s.level
+	s.level = level
+	s.resetRules()
+	for _, rule := range rules {
+		if rule != nil {
+			id, _ := c.addRule(s, "", rule)
+			added = appendId(added, id)
+		}
+	}
+	filtersChanged := false
+	if withFilters {
+		filtersChanged = !sameFilters(s.filters, filters)
+		s.filters = sliceCopy(filters)
+	}
+	c.publish(s)
+	c.scheduleRuleChange(s)
+	if old != level {
+		c.changed(ChangeDefaultLevel, func(e *ChangeEvent) { e.Level = level })
+	}
+	c.rulesChanged(ChangeRuleRemoved, removed)
+	c.rulesChanged(ChangeRuleAdded, added)
+	if filtersChanged {
+		c.changed(ChangeFilters)
+	}
+}
+
+// scheduleRuleChange schedules the handling of the next activation
+// change of temporary rules in the rule set.
+// It must be called with the lock held.
+func (c *context) scheduleRuleChange(s *state) {
+	now := time.Now()
+
+	var next time.Time
+	for _, e := range s.rules {
+		if t, ok := e.Rule.(TemporaryRule); ok {
+			n := t.NextChange(now)
+			if !n.IsZero() && (next.IsZero() || n.Before(next)) {
+				next = n
+			}
+		}
+	}
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+	if !next.IsZero() {
+		c.timer = time.AfterFunc(next.Sub(now), c.handleRuleChange)
+	}
+}
+
+// handleRuleChange removes expired rules and propagates
+// the activation change of temporary rules.
+func (c *context) handleRuleChange() {
+	c.lock.Lock()
+	defer c.unlock()
+
+	var removed []string
+	now := time.Now()
+	s := c.modify()
+	i := 0
+	for i < len(s.rules) {
+		if t, ok := s.rules[i].Rule.(TemporaryRule); ok && t.Expired(now) {
+			removed = append(removed, s.rules[i].Id)
+			s.rules = append(s.rules[:i:i], s.rules[i+1:]...)
+			s.index = nil
+		} else {
+			i++
+		}
+	}
+	c.publish(s)
+	c.scheduleRuleChange(s)
+	c.rulesChanged(ChangeRuleRemoved, removed)
 }
 
 func (c *context) WithContext(messageContext ...MessageContext) Context {
@@ -266,14 +680,15 @@ func (c *context) Match(cond Condition) bool {
 }
 
 func (c *context) LoggerFor(messageContext ...MessageContext) Logger {
-	c.lock.RLock()
-	defer c.lock.RUnlock()
-
 	messageContext = explode(messageContext)
 	l := c.evaluate(c.GetSink, messageContext...)
 	if l == nil {
-		return NonLoggingLogger
+		// fatal records are always emitted to the base sink.
+		l = nologger{sink: c.GetSink()}
+	} else {
+		l = filtered(l, c.GetFilters())
 	}
+	l = owned(l, c)
 	for _, c := range messageContext {
 		if a, ok := c.(Attacher); ok {
 			l = a.Attach(l)
@@ -283,17 +698,44 @@ func (c *context) LoggerFor(messageContext ...MessageContext) Logger {
 }
 
 func (c *context) Logger(messageContext ...MessageContext) Logger {
-	c.lock.RLock()
-	defer c.lock.RUnlock()
-
 	messageContext = explode(messageContext)
 	if len(c.messageContext) > 0 {
 		messageContext = JoinMessageContext(c.messageContext, messageContext...)
 	}
+
+	key, ok := cacheKey(messageContext)
+	if !ok {
+		return c.logger(messageContext...)
+	}
+
+	// get watermark first to assure the cache is at least valid for
+	// the actual watermark (see dynamicLogger).
+	watermark := c.updater.Watermark()
+	cache := c.cache.Load()
+	if cache == nil || cache.watermark < watermark {
+		n := newEvaluationCache(watermark)
+		if c.cache.CompareAndSwap(cache, n) {
+			cache = n
+		} else {
+			cache = c.cache.Load()
+		}
+	}
+	if l := cache.get(key); l != nil {
+		return l
+	}
+	l := c.logger(messageContext...)
+	if cache.watermark == watermark {
+		cache.put(key, l)
+	}
+	return l
+}
+
+func (c *context) logger(messageContext ...MessageContext) Logger {
 	l := c.evaluate(c.GetSink, messageContext...)
 	if l == nil {
 		l = c.defaultLogger
 	}
+	l = owned(filtered(l, c.GetFilters()), c)
 	for _, c := range messageContext {
 		if a, ok := c.(Attacher); ok {
 			l = a.Attach(l)
@@ -310,25 +752,61 @@ func (c *context) Evaluate(base SinkFunc, messageContext ...MessageContext) Logg
 	if len(messageContext) > 0 {
 		messageContext = explode(messageContext)
 	}
-	c.lock.RLock()
-	defer c.lock.RUnlock()
-
 	return c.evaluate(base, messageContext...)
 }
 
 func (c *context) evaluate(base SinkFunc, messageContext ...MessageContext) Logger {
-	for _, rule := range c.rules {
-		l := rule.Match(base, messageContext...)
+	s := c.state.Load()
+	idx := s.index
+
+	// indexed rules always match, so only linear rules
+	// located before the first matching indexed rule are relevant.
+	first := idx.first(messageContext...)
+	for _, i := range idx.linear {
+		if first >= 0 && i > first {
+			break
+		}
+		l := matchRule(c, s.rules[i].Rule, base, messageContext...)
 		if l != nil {
 			return l
 		}
 	}
+	if first >= 0 {
+		return matchRule(c, s.rules[first].Rule, base, messageContext...)
+	}
 	if c.base != nil {
 		return c.base.Evaluate(base, messageContext...)
 	}
 	return nil
 }
 
+func (c *context) Explain(messageContext ...MessageContext) *Explanation {
+	messageContext = explode(messageContext)
+	if len(c.messageContext) > 0 {
+		messageContext = JoinMessageContext(c.messageContext, messageContext...)
+	}
+	e := c.ExplainEvaluation(c.GetSink, messageContext...)
+	if e.Rule == nil {
+		e.Default = true
+		e.Context = c
+		e.Level = c.GetDefaultLevel()
+	}
+	return e
+}
+
+func (c *context) ExplainEvaluation(base SinkFunc, messageContext ...MessageContext) *Explanation {
+	e := &Explanation{MessageContext: messageContext}
+	if !explainRules(e, c, c.state.Load().rules, base, messageContext...) && c.base != nil {
+		b := c.base.Tree().ExplainEvaluation(base, messageContext...)
+		e.Skipped = append(e.Skipped, b.Skipped...)
+		e.Rule = b.Rule
+		e.RuleId = b.RuleId
+		e.Context = b.Context
+		e.Level = b.Level
+	}
+	return e
+}
+
 type levelCatcher struct {
 	sink
 }
@@ -41,4 +41,14 @@ type ContextSupport interface {
 	// This writer must explicitly be provided appropriately for the given
 	// logr logger, when creating the logging context-
 	LogWriter() io.Writer
+
+	// GetFilters returns the effective filters of a context
+	// including the filters of base contexts.
+	GetFilters() []Filter
+
+	// ExplainEvaluation explains the evaluation of the rule set of the
+	// context tree for an already flattened message context without
+	// using any default (see Context.Evaluate).
+	// If no rule matches, the Rule field of the result is not set.
+	ExplainEvaluation(base SinkFunc, messageContext ...MessageContext) *Explanation
 }
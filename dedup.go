@@ -0,0 +1,343 @@
+/*
+ * Copyright 2023 Mandelsoft. All rights reserved.
+ *  This file is licensed under the Apache Software License, v. 2 except as noted
+ *  otherwise in the LICENSE file
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package logging
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// FieldKeyRepeated is the name of the logr field used to report the number
+// of suppressed repetitions of a log message.
+const FieldKeyRepeated = "repeated"
+
+// Flusher is the optional interface for sinks and rules
+// buffering log information, which must be flushed
+// before terminating a program.
+type Flusher interface {
+	Flush()
+}
+
+// Flush flushes all pending log information of the rules and sinks
+// of a logging context and its base contexts.
+func Flush(ctx Context) {
+	for ctx != nil {
+		for _, e := range ctx.Rules() {
+			if f, ok := e.Rule.(Flusher); ok {
+				f.Flush()
+			}
+		}
+		FlushSink(ctx.GetSink())
+		ctx = ctx.Tree().GetBaseContext()
+	}
+}
+
+// FlushSink flushes all pending log information of a sink
+// and the sinks wrapped by it.
+func FlushSink(s logr.LogSink) {
+	for s != nil {
+		if f, ok := s.(Flusher); ok {
+			f.Flush()
+		}
+		u, ok := s.(interface{ Unwrap() logr.LogSink })
+		if !ok {
+			return
+		}
+		m := u.Unwrap()
+		if m == s {
+			return
+		}
+		s = m
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// NewDeduplicatingSink wraps a sink to suppress identical consecutive
+// log records for the same logger name and realm issued within the given
+// time window. The first record is passed, the number of suppressed repetitions
+// is reported later by a summary record with the additional field
+// FieldKeyRepeated. This summary is issued when the time window is closed,
+// for the next differing record, or when the sink is flushed (see FlushSink).
+func NewDeduplicatingSink(sink logr.LogSink, window time.Duration) logr.LogSink {
+	return &dedupSink{
+		state: newDedupState(window),
+		sink:  withCallDepth(sink, 1),
+	}
+}
+
+// DeduplicationRule is a rule enabling a log level for a message context
+// matching all given conditions, which suppresses identical consecutive log
+// records (see NewDeduplicatingSink).
+type DeduplicationRule struct {
+	conditions []Condition
+	level      int
+	state      *dedupState
+}
+
+var _ UpdatableRule = (*DeduplicationRule)(nil)
+var _ Flusher = (*DeduplicationRule)(nil)
+
+// NewDeduplicationRule provides a rule enabling the given level for a message
+// context matching all given conditions. All loggers provided by the rule
+// share the deduplication state.
+func NewDeduplicationRule(level int, window time.Duration, cond ...Condition) Rule {
+	return &DeduplicationRule{
+		conditions: cond,
+		level:      level,
+		state:      newDedupState(window),
+	}
+}
+
+func (r *DeduplicationRule) MatchRule(o Rule) bool {
+	if or, ok := o.(*DeduplicationRule); ok {
+		return reflect.DeepEqual(r.conditions, or.conditions)
+	}
+	return false
+}
+
+func (r *DeduplicationRule) Match(sink SinkFunc, messageContext ...MessageContext) Logger {
+	for _, c := range r.conditions {
+		if !c.Match(messageContext...) {
+			return nil
+		}
+	}
+
+	return NewLogger(&dedupSink{state: r.state, sink: withCallDepth(DynSink(AsLevelFunc(r.level), 0, sink), 1)})
+}
+
+func (r *DeduplicationRule) Flush() {
+	r.state.flush()
+}
+
+func (r *DeduplicationRule) Level() int {
+	return r.level
+}
+
+func (r *DeduplicationRule) Window() time.Duration {
+	return r.state.window
+}
+
+func (r *DeduplicationRule) Conditions() []Condition {
+	return sliceCopy(r.conditions)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+type dedupRecord struct {
+	// sink is adjusted for the frames of summary and emit
+	// to report the caller of the logging method.
+	sink   logr.LogSink
+	values []interface{}
+	error  bool
+	err    error
+	level  int
+	msg    string
+	kv     []interface{}
+}
+
+func (r *dedupRecord) same(o *dedupRecord) bool {
+	return r.error == o.error && r.level == o.level && r.msg == o.msg &&
+		reflect.DeepEqual(r.err, o.err) &&
+		reflect.DeepEqual(r.kv, o.kv) &&
+		reflect.DeepEqual(r.values, o.values)
+}
+
+func (r *dedupRecord) emit(kv ...interface{}) {
+	kv = append(sliceCopy(r.kv), kv...)
+	if r.error {
+		r.sink.Error(r.err, r.msg, kv...)
+	} else {
+		r.sink.Info(r.level, r.msg, kv...)
+	}
+}
+
+type dedupEntry struct {
+	record  *dedupRecord
+	first   time.Time
+	repeats int
+	timer   *time.Timer
+}
+
+func (e *dedupEntry) stop() {
+	if e.timer != nil {
+		e.timer.Stop()
+	}
+}
+
+func (e *dedupEntry) summary() {
+	if e.repeats > 0 {
+		e.record.emit(FieldKeyRepeated, e.repeats)
+	}
+}
+
+type dedupState struct {
+	lock    sync.Mutex
+	window  time.Duration
+	entries map[string]*dedupEntry
+}
+
+func newDedupState(window time.Duration) *dedupState {
+	return &dedupState{
+		window:  window,
+		entries: map[string]*dedupEntry{},
+	}
+}
+
+// check registers a record and returns whether it should
+// be passed. Additionally, the replaced entry for a former record
+// is returned, whose summary must be issued by the caller before
+// passing the record.
+// The summary for repeated records is issued by a timer when the time
+// window is closed. Whoever removes an entry is responsible for its summary.
+func (s *dedupState) check(key string, r *dedupRecord) (bool, *dedupEntry) {
+	now := time.Now()
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	e := s.entries[key]
+	if e != nil && e.record.same(r) && now.Sub(e.first) < s.window {
+		e.repeats++
+		if e.timer == nil {
+			e.timer = time.AfterFunc(s.window-now.Sub(e.first), func() { s.expire(key, e) })
+		}
+		return false, nil
+	}
+	s.entries[key] = &dedupEntry{record: r, first: now}
+	if e != nil {
+		e.stop()
+	}
+	return true, e
+}
+
+// expire issues the summary for an entry at the end of its time window.
+func (s *dedupState) expire(key string, e *dedupEntry) {
+	s.lock.Lock()
+	if s.entries[key] != e {
+		s.lock.Unlock()
+		return
+	}
+	delete(s.entries, key)
+	s.lock.Unlock()
+
+	e.summary()
+}
+
+func (s *dedupState) flush() {
+	s.lock.Lock()
+	entries := s.entries
+	s.entries = map[string]*dedupEntry{}
+	s.lock.Unlock()
+
+	for _, e := range entries {
+		e.stop()
+		e.summary()
+	}
+}
+
+type dedupSink struct {
+	state  *dedupState
+	sink   logr.LogSink
+	name   []string
+	realm  string
+	values []interface{}
+}
+
+var _ logr.CallDepthLogSink = (*dedupSink)(nil)
+var _ filteringSink = (*dedupSink)(nil)
+var _ Flusher = (*dedupSink)(nil)
+
+func (s *dedupSink) key() string {
+	return strings.Join(s.name, ".") + "\x00" + s.realm
+}
+
+func (s *dedupSink) Init(info logr.RuntimeInfo) {
+}
+
+func (s *dedupSink) Enabled(level int) bool {
+	return s.sink.Enabled(level)
+}
+
+func (s *dedupSink) Info(level int, msg string, keysAndValues ...interface{}) {
+	if !s.sink.Enabled(level) {
+		return
+	}
+	r := &dedupRecord{sink: withCallDepth(s.sink, 2), values: s.values, level: level, msg: msg, kv: keysAndValues}
+	pass, former := s.state.check(s.key(), r)
+	if former != nil {
+		former.summary()
+	}
+	if pass {
+		s.sink.Info(level, msg, keysAndValues...)
+	}
+}
+
+func (s *dedupSink) Error(err error, msg string, keysAndValues ...interface{}) {
+	r := &dedupRecord{sink: withCallDepth(s.sink, 2), values: s.values, error: true, err: err, msg: msg, kv: keysAndValues}
+	pass, former := s.state.check(s.key(), r)
+	if former != nil {
+		former.summary()
+	}
+	if pass {
+		s.sink.Error(err, msg, keysAndValues...)
+	}
+}
+
+func (s *dedupSink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	n := *s
+	n.sink = s.sink.WithValues(keysAndValues...)
+	n.values = sliceAppend(s.values, keysAndValues...)
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		if keysAndValues[i] == FieldKeyRealm {
+			if r, ok := keysAndValues[i+1].(string); ok {
+				n.realm = r
+			}
+		}
+	}
+	return &n
+}
+
+func (s *dedupSink) WithName(name string) logr.LogSink {
+	n := *s
+	n.sink = s.sink.WithName(name)
+	n.name = sliceAppend(s.name, name)
+	return &n
+}
+
+func (s *dedupSink) WithCallDepth(depth int) logr.LogSink {
+	n := *s
+	n.sink = withCallDepth(s.sink, depth)
+	return &n
+}
+
+func (s *dedupSink) Flush() {
+	s.state.flush()
+}
+
+func (s *dedupSink) Unwrap() logr.LogSink {
+	return s.sink
+}
+
+func (s *dedupSink) unfiltered() logr.LogSink {
+	return s.sink
+}
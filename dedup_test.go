@@ -0,0 +1,180 @@
+/*
+ * Copyright 2023 Mandelsoft. All rights reserved.
+ *  This file is licensed under the Apache Software License, v. 2 except as noted
+ *  otherwise in the LICENSE file
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package logging_test
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/go-logr/logr"
+	"github.com/tonglil/buflogr"
+
+	"github.com/mandelsoft/logging"
+	"github.com/mandelsoft/logging/logrusl"
+)
+
+// syncBuffer is a buffer, which can be written by timers.
+type syncBuffer struct {
+	lock sync.Mutex
+	buf  bytes.Buffer
+}
+
+func (b *syncBuffer) Write(data []byte) (int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buf.Write(data)
+}
+
+func (b *syncBuffer) String() string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buf.String()
+}
+
+var _ = Describe("deduplication", func() {
+	var buf bytes.Buffer
+
+	realm := logging.NewRealm("realm")
+
+	BeforeEach(func() {
+		buf.Reset()
+	})
+
+	Context("sink", func() {
+		var ctx logging.Context
+
+		BeforeEach(func() {
+			ctx = logging.New(logr.New(logging.NewDeduplicatingSink(buflogr.NewWithBuffer(&buf).GetSink(), time.Hour)))
+		})
+
+		It("collapses identical records", func() {
+			for i := 0; i < 5; i++ {
+				ctx.Logger(realm).Info("loop", "key", "value")
+			}
+			ctx.Logger().Info("loop", "key", "value")
+			ctx.Logger(realm).Info("done")
+
+			Expect("\n" + buf.String()).To(Equal(`
+V[3] loop realm realm key value
+V[3] loop key value
+V[3] loop realm realm key value repeated 4
+V[3] done realm realm
+`))
+		})
+
+		It("distinguishes names and values", func() {
+			ctx.Logger().WithName("a").Info("loop")
+			ctx.Logger().WithName("b").Info("loop")
+			ctx.Logger().WithName("a").Info("loop")
+			ctx.Logger().WithName("a").WithValues("id", 1).Info("loop")
+			ctx.Logger().WithName("a").WithValues("id", 1).Info("loop")
+
+			Expect("\n" + buf.String()).To(Equal(`
+V[3] a loop
+V[3] b loop
+V[3] a loop repeated 1
+V[3] a loop id 1
+`))
+			buf.Reset()
+			logging.Flush(ctx)
+			Expect("\n" + buf.String()).To(Equal(`
+V[3] a loop id 1 repeated 1
+`))
+		})
+
+		It("flushes pending summaries", func() {
+			for i := 0; i < 3; i++ {
+				ctx.Logger().Error("failed")
+			}
+			logging.Flush(ctx)
+			logging.Flush(ctx)
+
+			Expect("\n" + buf.String()).To(Equal(`
+ERROR <nil> failed
+ERROR <nil> failed repeated 2
+`))
+		})
+	})
+
+	Context("rule", func() {
+		It("collapses records for matching message context", func() {
+			ctx := logging.New(buflogr.NewWithBuffer(&buf))
+			ctx.AddRule(logging.NewDeduplicationRule(logging.DebugLevel, time.Hour, realm))
+
+			for i := 0; i < 3; i++ {
+				ctx.Logger(realm).Debug("loop")
+				ctx.Logger().Info("other")
+			}
+			nested := logging.NewWithBase(ctx)
+			logging.Flush(nested)
+
+			Expect("\n" + buf.String()).To(Equal(`
+V[4] loop realm realm
+V[3] other
+V[3] other
+V[3] other
+V[4] loop realm realm repeated 2
+`))
+		})
+
+		It("passes records after window", func() {
+			var buf syncBuffer
+			ctx := logrusl.WithWriter(&buf).JSON().New()
+			ctx.AddRule(logging.NewDeduplicationRule(logging.DebugLevel, 50*time.Millisecond, realm))
+
+			ctx.Logger(realm).Debug("loop")
+			ctx.Logger(realm).Debug("loop")
+			Eventually(buf.String, time.Second, 10*time.Millisecond).Should(ContainSubstring(`"repeated":1`))
+			ctx.Logger(realm).Debug("loop")
+
+			Expect(strings.Count(buf.String(), `"msg":"loop"`)).To(Equal(3))
+			Expect(strings.Count(buf.String(), `"repeated"`)).To(Equal(1))
+		})
+
+		It("issues summary when window is closed", func() {
+			var buf syncBuffer
+			ctx := logrusl.WithWriter(&buf).JSON().New()
+			ctx.AddRule(logging.NewDeduplicationRule(logging.InfoLevel, 50*time.Millisecond, realm))
+			for i := 0; i < 3; i++ {
+				ctx.Logger(realm).Info("loop")
+			}
+			Expect(strings.Count(buf.String(), `"msg":"loop"`)).To(Equal(1))
+			Eventually(buf.String, time.Second, 10*time.Millisecond).Should(ContainSubstring(`"repeated":2`))
+			Expect(strings.Count(buf.String(), `"msg":"loop"`)).To(Equal(2))
+
+			logging.Flush(ctx)
+			Expect(strings.Count(buf.String(), `"msg":"loop"`)).To(Equal(2))
+		})
+	})
+
+	It("is configured by logrus settings", func() {
+		ctx := logrusl.WithWriter(&buf).WithDeduplication(time.Hour).JSON().New()
+		for i := 0; i < 3; i++ {
+			ctx.Logger().Info("loop")
+		}
+		logging.Flush(ctx)
+		Expect(strings.Count(buf.String(), `"msg":"loop"`)).To(Equal(2))
+		Expect(buf.String()).To(ContainSubstring(`"repeated":2`))
+	})
+})
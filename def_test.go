@@ -46,6 +46,7 @@ var _ = Describe("definitions", func() {
 			"tag1": []string{"tag1 desc 1", "tag1 desc 2"},
 			"tag2": []string{"tag2 desc 1"},
 		}))
+		Expect(logging.GetTagTree().String()).To(Equal("tag1: tag1 desc 1; tag1 desc 2\ntag2: tag2 desc 1\n"))
 		Expect(logging.GetRealmDefinitions()).To(Equal(logging.Definitions{
 			"realm1": []string{"realm1 desc 1", "realm1 desc 2"},
 			"realm2": []string{"realm2 desc 1"},
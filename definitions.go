@@ -20,6 +20,7 @@ package logging
 
 import (
 	"sort"
+	"strings"
 	"sync"
 )
 
@@ -38,6 +39,86 @@ func GetRealmDefinitions() Definitions {
 	return defs.GetRealms()
 }
 
+// GetTagTree provides the hierarchy of the defined tags.
+func GetTagTree() *DefinitionNode {
+	return defs.GetTagTree()
+}
+
+// DefinitionNode describes a node in the hierarchy of defined
+// names. Names are structured by dots or slashes. The Path is the complete
+// name of the node, the Name the last segment. Intermediate nodes not
+// defined on their own are not marked as Defined.
+type DefinitionNode struct {
+	Name         string
+	Path         string
+	Defined      bool
+	Descriptions []string
+	Children     []*DefinitionNode
+}
+
+// Tree provides the hierarchy of the names in a set of definitions.
+// The root node has an empty name, the children of every node are
+// ordered by name.
+func (d Definitions) Tree() *DefinitionNode {
+	names := make([]string, 0, len(d))
+	for n := range d {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	root := &DefinitionNode{}
+	for _, n := range names {
+		node := root
+		start := 0
+		for i := 0; i <= len(n); i++ {
+			if i < len(n) && !isTagSeparator(n[i]) {
+				continue
+			}
+			node = node.child(n[start:i], n[:i])
+			start = i + 1
+		}
+		node.Defined = true
+		node.Descriptions = sliceCopy(d[n])
+	}
+	return root
+}
+
+func (n *DefinitionNode) child(name, path string) *DefinitionNode {
+	for _, c := range n.Children {
+		if c.Path == path {
+			return c
+		}
+	}
+	c := &DefinitionNode{Name: name, Path: path}
+	n.Children = append(n.Children, c)
+	sort.Slice(n.Children, func(i, j int) bool { return n.Children[i].Name < n.Children[j].Name })
+	return c
+}
+
+// String provides an indented representation of the hierarchy
+// with the descriptions of the nodes.
+func (n *DefinitionNode) String() string {
+	var b strings.Builder
+	n.format(&b, "")
+	return b.String()
+}
+
+func (n *DefinitionNode) format(b *strings.Builder, indent string) {
+	if n.Path != "" {
+		b.WriteString(indent)
+		b.WriteString(n.Name)
+		if len(n.Descriptions) > 0 {
+			b.WriteString(": ")
+			b.WriteString(strings.Join(n.Descriptions, "; "))
+		}
+		b.WriteString("\n")
+		indent += "  "
+	}
+	for _, c := range n.Children {
+		c.format(b, indent)
+	}
+}
+
 type definitions struct {
 	lock   sync.Mutex
 	tags   map[string][]string
@@ -80,6 +161,13 @@ func (d *definitions) GetTags() map[string][]string {
 	return d.tags
 }
 
+func (d *definitions) GetTagTree() *DefinitionNode {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	return Definitions(d.tags).Tree()
+}
+
 func (d *definitions) GetRealms() map[string][]string {
 	d.lock.Lock()
 	defer d.lock.Unlock()
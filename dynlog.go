@@ -26,9 +26,13 @@ import (
 
 type dynamicLogger struct {
 	attribution AttributionContext
+	depth       int
 	lock        sync.Mutex
 	watermark   int64
 	logger      Logger
+	// delegate is the logger used to issue log records
+	// by this logger. It accounts for the additional call frame.
+	delegate Logger
 }
 
 var _ Logger = (*dynamicLogger)(nil)
@@ -49,9 +53,23 @@ func DynamicLogger(ctxp AttributionContextProvider, messageContext ...MessageCon
 	return l
 }
 
+// update provides the delegate for the actual configuration.
 func (d *dynamicLogger) update() Logger {
 	d.lock.Lock()
 	defer d.lock.Unlock()
+	d.refresh()
+	return d.delegate
+}
+
+// bound provides the bound logger for the actual configuration.
+func (d *dynamicLogger) bound() Logger {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.refresh()
+	return d.logger
+}
+
+func (d *dynamicLogger) refresh() {
 	// get watermark first to assure logger for at least the actual watermark.
 	// this is not accurate in the sense of not necessarily being uptodate
 	// with intermediate config requests, but this glitch does not hamper,
@@ -60,10 +78,10 @@ func (d *dynamicLogger) update() Logger {
 	watermark := d.LoggingContext().Tree().Updater().Watermark()
 	if d.logger == nil || watermark > d.watermark {
 		// update logger and incorporate local modifications
-		d.logger = d.attribution.Logger()
+		d.logger = d.attribution.Logger().WithCallDepth(d.depth)
+		d.delegate = d.logger.WithCallDepth(1)
 		d.watermark = watermark
 	}
-	return d.logger
 }
 
 func (d *dynamicLogger) LoggingContext() Context {
@@ -98,6 +116,20 @@ func (d *dynamicLogger) Trace(msg string, keypairs ...interface{}) {
 	d.update().Trace(msg, keypairs...)
 }
 
+func (d *dynamicLogger) Log(level int, msg string, keypairs ...interface{}) {
+	d.update().Log(level, msg, keypairs...)
+}
+
+func (d *dynamicLogger) Fatal(msg string, keypairs ...interface{}) {
+	Flush(d.LoggingContext())
+	d.update().Fatal(msg, keypairs...)
+}
+
+func (d *dynamicLogger) Panic(msg string, keypairs ...interface{}) {
+	Flush(d.LoggingContext())
+	d.update().Panic(msg, keypairs...)
+}
+
 func (d *dynamicLogger) GetMessageContext() []MessageContext {
 	return d.attribution.GetMessageContext()
 }
@@ -106,6 +138,7 @@ func (d *dynamicLogger) WithName(name string) Logger {
 	l := *d
 	l.attribution = l.attribution.WithName(name)
 	l.logger = nil
+	l.delegate = nil
 	return &l
 }
 
@@ -116,6 +149,7 @@ func (d *dynamicLogger) WithValues(keypairs ...interface{}) Logger {
 	l := *d
 	l.attribution = l.attribution.WithValues(keypairs...)
 	l.logger = nil
+	l.delegate = nil
 	return &l
 }
 
@@ -126,17 +160,25 @@ func (d *dynamicLogger) WithContext(messageContext ...MessageContext) UnboundLog
 	l := *d
 	l.attribution = l.attribution.WithContext(messageContext...)
 	l.logger = nil
+	l.delegate = nil
 	return &l
 }
 
+func (d *dynamicLogger) WithCallDepth(depth int) Logger {
+	return &dynamicLogger{
+		attribution: d.attribution,
+		depth:       d.depth + depth,
+	}
+}
+
 func (d *dynamicLogger) Enabled(level int) bool {
 	return d.update().Enabled(level)
 }
 
 func (d *dynamicLogger) V(delta int) logr.Logger {
-	return d.update().V(delta)
+	return d.bound().V(delta)
 }
 
 func (d *dynamicLogger) BoundLogger() Logger {
-	return d.update()
+	return d.bound()
 }
@@ -0,0 +1,247 @@
+/*
+ * Copyright 2023 Mandelsoft. All rights reserved.
+ *  This file is licensed under the Apache Software License, v. 2 except as noted
+ *  otherwise in the LICENSE file
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package logging
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxExplainLevel is the highest level probed to determine
+// the effective level of a logger provided by a rule.
+const maxExplainLevel = 100
+
+// Explanation describes, how the effective logger for a
+// message context has been determined by a logging context.
+type Explanation struct {
+	// MessageContext is the effective (flattened) message context
+	// used for the rule evaluation.
+	MessageContext []MessageContext
+	// Rule is the rule, which decided about the logger.
+	// It is nil, if the default logger has been used.
+	Rule Rule
+	// RuleId is the id of the matched rule in its context.
+	RuleId string
+	// Context is the context in the context tree owning the
+	// matched rule. If no rule matched, it is the context
+	// providing the default logger.
+	Context Context
+	// Level is the effective log level.
+	Level int
+	// Default is true, if no rule matched and the default logger
+	// of the context has been used.
+	Default bool
+	// Skipped lists the rules evaluated before the
+	// decision has been taken in evaluation order.
+	Skipped []SkippedRule
+}
+
+// SkippedRule describes a rule not matching a message context.
+type SkippedRule struct {
+	// Context is the context in the context tree owning the rule.
+	Context Context
+	// Rule is the skipped rule.
+	Rule Rule
+	// RuleId is the id of the skipped rule in its context.
+	RuleId string
+	// Failed lists the conditions of the rule, which did not
+	// match the message context. It is only provided for rules
+	// offering access to their conditions (like ConditionRule).
+	Failed []Condition
+}
+
+func (e *Explanation) String() string {
+	var s strings.Builder
+
+	fmt.Fprintf(&s, "message context: %s\n", describeMessageContext(e.MessageContext))
+	for _, r := range e.Skipped {
+		fmt.Fprintf(&s, "skipped rule %s %s (context %s)\n", r.RuleId, describeRule(r.Rule), describeContext(r.Context))
+		for _, c := range r.Failed {
+			fmt.Fprintf(&s, "  failed condition %s\n", describeCondition(c))
+		}
+	}
+	if e.Default {
+		fmt.Fprintf(&s, "no rule matched: using default logger (context %s)\n", describeContext(e.Context))
+	} else {
+		fmt.Fprintf(&s, "matched rule %s %s (context %s)\n", e.RuleId, describeRule(e.Rule), describeContext(e.Context))
+	}
+	fmt.Fprintf(&s, "effective level: %s\n", LevelName(e.Level))
+	return s.String()
+}
+
+// explainRules explains the evaluation of a rule list
+// for a message context. If no rule matches, the Rule field of the
+// explanation is not set.
+func explainRules(e *Explanation, ctx Context, rules []RuleEntry, base SinkFunc, messageContext ...MessageContext) bool {
+	for _, r := range rules {
+		l := matchRule(ctx, r.Rule, base, messageContext...)
+		if l != nil {
+			e.Rule = r.Rule
+			e.RuleId = r.Id
+			e.Context = ctx
+			e.Level = effectiveLevel(l)
+			return true
+		}
+		e.Skipped = append(e.Skipped, SkippedRule{
+			Context: ctx,
+			Rule:    r.Rule,
+			RuleId:  r.Id,
+			Failed:  failedConditions(r.Rule, messageContext...),
+		})
+	}
+	return false
+}
+
+// effectiveLevel determines the highest level enabled for a logger.
+func effectiveLevel(l Logger) int {
+	level := None
+	for level < maxExplainLevel && l.Enabled(level+1) {
+		level++
+	}
+	return level
+}
+
+func failedConditions(rule Rule, messageContext ...MessageContext) []Condition {
+	var failed []Condition
+
+	if p, ok := rule.(interface{ Conditions() []Condition }); ok {
+		for _, c := range p.Conditions() {
+			if !c.Match(messageContext...) {
+				failed = append(failed, c)
+			}
+		}
+	}
+	return failed
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func describeContext(ctx Context) string {
+	if c, ok := ctx.(*context); ok {
+		return fmt.Sprintf("%d", c.id)
+	}
+	return fmt.Sprintf("%T", ctx)
+}
+
+func describeRule(rule Rule) string {
+	switch r := rule.(type) {
+	case fmt.Stringer:
+		return r.String()
+	case *ConditionRule:
+		return fmt.Sprintf("%s%s", LevelName(r.level), describeConditions(r.conditions))
+	case *SamplingRule:
+		return fmt.Sprintf("sampling %s%s", LevelName(r.level), describeConditions(r.conditions))
+	case *DeduplicationRule:
+		return fmt.Sprintf("deduplication %s%s", LevelName(r.level), describeConditions(r.conditions))
+	case *RoutingRule:
+		level := "default"
+		if r.level >= 0 {
+			level = LevelName(r.level)
+		}
+		target := "sink"
+		if r.name != "" {
+			target = "sink " + r.name
+		}
+		return fmt.Sprintf("route to %s %s%s", target, level, describeConditions(r.conditions))
+	default:
+		return fmt.Sprintf("%T", rule)
+	}
+}
+
+func describeConditions(conditions []Condition) string {
+	list := make([]string, len(conditions))
+	for i, c := range conditions {
+		list[i] = describeCondition(c)
+	}
+	return "[" + strings.Join(list, ", ") + "]"
+}
+
+func describeCondition(cond Condition) string {
+	switch c := cond.(type) {
+	case *AndExpr:
+		return "and" + describeConditions(c.conditions)
+	case *OrExpr:
+		return "or" + describeConditions(c.conditions)
+	case *NotExpr:
+		return "not[" + describeCondition(c.condition) + "]"
+	default:
+		return describeMessageContextElement(cond)
+	}
+}
+
+func describeMessageContext(messageContext []MessageContext) string {
+	list := make([]string, len(messageContext))
+	for i, m := range messageContext {
+		list[i] = describeMessageContextElement(m)
+	}
+	return "[" + strings.Join(list, ", ") + "]"
+}
+
+func describeMessageContextElement(m MessageContext) string {
+	switch e := m.(type) {
+	case Realm:
+		return fmt.Sprintf("realm %s", e.Name())
+	case RealmPrefix:
+		return fmt.Sprintf("realmprefix %s", e.Name())
+	case *RealmPattern:
+		return fmt.Sprintf("realmpattern %s", e.Pattern())
+	case *RealmRegex:
+		return fmt.Sprintf("realmregex %s", e.Expression())
+	case Tag:
+		return fmt.Sprintf("tag %s", e.Name())
+	case TagPrefix:
+		return fmt.Sprintf("tagprefix %s", e.Name())
+	case *AnyTag:
+		return fmt.Sprintf("anytag %v", e.tags)
+	case *AllTags:
+		return fmt.Sprintf("alltags %v", e.tags)
+	case Name:
+		return fmt.Sprintf("name %s", e.Name())
+	case Attribute:
+		if v, ok := e.Value().(ValueMatcher); ok {
+			return fmt.Sprintf("attribute %s %s", e.Name(), describeValueMatcher(v))
+		}
+		return fmt.Sprintf("attribute %s=%v", e.Name(), e.Value())
+	case fmt.Stringer:
+		return e.String()
+	default:
+		return fmt.Sprintf("%T", m)
+	}
+}
+
+func describeValueMatcher(m ValueMatcher) string {
+	switch v := m.(type) {
+	case *EqualsMatcher:
+		return fmt.Sprintf("equals %v", v.value)
+	case *RegexMatcher:
+		return fmt.Sprintf("matches %s", v.expr)
+	case *GlobMatcher:
+		return fmt.Sprintf("like %s", v.pattern)
+	case *InMatcher:
+		return fmt.Sprintf("in %v", v.values)
+	case *RangeMatcher:
+		return fmt.Sprintf("in range [%v, %v]", v.min, v.max)
+	case PresentMatcher:
+		return "present"
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprintf("%T", m)
+	}
+}
@@ -0,0 +1,101 @@
+/*
+ * Copyright 2023 Mandelsoft. All rights reserved.
+ *  This file is licensed under the Apache Software License, v. 2 except as noted
+ *  otherwise in the LICENSE file
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package logging_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/tonglil/buflogr"
+
+	"github.com/mandelsoft/logging"
+)
+
+var _ = Describe("explain", func() {
+	var buf bytes.Buffer
+	var ctx logging.Context
+
+	realm := logging.NewRealm("realm")
+	tag := logging.NewTag("tag")
+
+	BeforeEach(func() {
+		buf.Reset()
+		ctx = logging.New(buflogr.NewWithBuffer(&buf))
+	})
+
+	It("explains default", func() {
+		ctx.SetDefaultLevel(logging.WarnLevel)
+		ctx.AddRule(logging.NewConditionRule(logging.DebugLevel, tag))
+
+		e := ctx.Explain(realm)
+		Expect(e.Default).To(BeTrue())
+		Expect(e.Rule).To(BeNil())
+		Expect(e.Context).To(BeIdenticalTo(ctx))
+		Expect(e.Level).To(Equal(logging.WarnLevel))
+		Expect(e.MessageContext).To(Equal([]logging.MessageContext{realm}))
+		Expect(len(e.Skipped)).To(Equal(1))
+		Expect(e.Skipped[0].Failed).To(Equal([]logging.Condition{tag}))
+	})
+
+	It("explains matching rule", func() {
+		rule := logging.NewConditionRule(logging.TraceLevel, realm)
+		ctx.AddRule(rule)
+		ctx.AddRule(logging.NewConditionRule(logging.DebugLevel, realm, tag))
+
+		e := ctx.Explain(realm)
+		Expect(e.Default).To(BeFalse())
+		Expect(e.Rule).To(BeIdenticalTo(rule))
+		Expect(e.RuleId).To(Equal("#1"))
+		Expect(e.Context).To(BeIdenticalTo(ctx))
+		Expect(e.Level).To(Equal(logging.TraceLevel))
+		Expect(len(e.Skipped)).To(Equal(1))
+		Expect(e.Skipped[0].Failed).To(Equal([]logging.Condition{tag}))
+		Expect(e.String()).To(MatchRegexp(`^message context: \[realm realm\]
+skipped rule #2 Debug\[realm realm, tag tag\] \(context [0-9]+\)
+  failed condition tag tag
+matched rule #1 Trace\[realm realm\] \(context [0-9]+\)
+effective level: Trace
+$`))
+	})
+
+	It("explains rule of base context", func() {
+		rule := logging.NewConditionRule(logging.DebugLevel, realm)
+		ctx.AddRule(rule)
+		nested := ctx.WithContext(realm)
+		nested.AddRule(logging.NewConditionRule(logging.TraceLevel, tag))
+
+		e := nested.Explain()
+		Expect(e.Rule).To(BeIdenticalTo(rule))
+		Expect(e.Context).To(BeIdenticalTo(ctx))
+		Expect(e.Level).To(Equal(logging.DebugLevel))
+		Expect(len(e.Skipped)).To(Equal(1))
+		Expect(e.Skipped[0].Context).To(BeIdenticalTo(nested))
+	})
+
+	It("explains for attribution context", func() {
+		rule := logging.NewConditionRule(logging.DebugLevel, realm, tag)
+		ctx.AddRule(rule)
+
+		e := ctx.AttributionContext().WithContext(realm).Explain(tag)
+		Expect(e.Rule).To(BeIdenticalTo(rule))
+		Expect(e.MessageContext).To(Equal([]logging.MessageContext{realm, tag}))
+	})
+})
@@ -0,0 +1,95 @@
+/*
+ * Copyright 2023 Mandelsoft. All rights reserved.
+ *  This file is licensed under the Apache Software License, v. 2 except as noted
+ *  otherwise in the LICENSE file
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package logging
+
+import (
+	"os"
+	"sync/atomic"
+
+	"github.com/go-logr/logr"
+)
+
+var exitHook atomic.Pointer[func(code int)]
+
+// SetExitHook replaces the function used by Logger.Fatal to terminate
+// the process after the log record has been emitted and flushed.
+// It returns the previously set hook. Passing nil restores the
+// default behaviour (os.Exit). Tests may use a hook, which
+// records the exit code and panics, instead.
+func SetExitHook(hook func(code int)) func(code int) {
+	var old *func(int)
+	if hook == nil {
+		old = exitHook.Swap(nil)
+	} else {
+		old = exitHook.Swap(&hook)
+	}
+	if old == nil {
+		return nil
+	}
+	return *old
+}
+
+func exit(code int) {
+	if hook := exitHook.Load(); hook != nil {
+		(*hook)(code)
+		return
+	}
+	os.Exit(code)
+}
+
+// filteringSink is implemented by sinks suppressing log records
+// (like sampling or deduplication). The unfiltered sink
+// is adjusted for the call frame of the filtering sink.
+type filteringSink interface {
+	unfiltered() logr.LogSink
+}
+
+// emitAlways emits an error record regardless of the activation of
+// the sink. Suppressing sinks provided by rules are bypassed.
+// Their frame is replaced by the frame of this function, otherwise
+// the call depth is adjusted accordingly.
+// Pending records are flushed before and after emitting the record.
+func emitAlways(s logr.LogSink, msg string, keypairs []interface{}) {
+	FlushSink(s)
+	if f, ok := s.(filteringSink); ok {
+		f.unfiltered().Error(nil, msg, prepare(keypairs)...)
+	} else {
+		withCallDepth(s, 1).Error(nil, msg, prepare(keypairs)...)
+	}
+	FlushSink(s)
+}
+
+// owned binds a logger provided by a logging context to this context.
+// The context is flushed by Fatal and Panic before emitting the record,
+// so that pending records of other message contexts are not lost.
+func owned(l Logger, ctx Context) Logger {
+	switch o := l.(type) {
+	case *logger:
+		return &logger{o.sink, ctx}
+	case nologger:
+		return nologger{o.sink, ctx}
+	}
+	return l
+}
+
+func flushContext(ctx Context) {
+	if ctx != nil {
+		Flush(ctx)
+	}
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright 2023 Mandelsoft. All rights reserved.
+ *  This file is licensed under the Apache Software License, v. 2 except as noted
+ *  otherwise in the LICENSE file
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package logging_test
+
+import (
+	"bytes"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/tonglil/buflogr"
+
+	"github.com/mandelsoft/logging"
+)
+
+type exited int
+
+var _ = Describe("fatal and panic", func() {
+	var buf bytes.Buffer
+	var ctx logging.Context
+
+	realm := logging.NewRealm("realm")
+
+	BeforeEach(func() {
+		buf.Reset()
+		ctx = logging.New(buflogr.NewWithBuffer(&buf))
+		old := logging.SetExitHook(func(code int) { panic(exited(code)) })
+		DeferCleanup(func() { logging.SetExitHook(old) })
+	})
+
+	It("emits fatal record and exits", func() {
+		ctx.SetDefaultLevel(logging.None)
+		logger := ctx.Logger(realm)
+		Expect(logger.Enabled(logging.ErrorLevel)).To(BeFalse())
+
+		Expect(func() { logger.Fatal("fatal", "key", "value") }).To(PanicWith(exited(1)))
+		Expect(buf.String()).To(Equal("ERROR <nil> fatal realm realm key value\n"))
+	})
+
+	It("bypasses sampling", func() {
+		ctx.AddRule(logging.NewSamplingRule(logging.InfoLevel, logging.Sampling{Every: 100, Errors: true}, realm))
+		logger := ctx.Logger(realm)
+		logger.Error("error")
+		logger.Error("error")
+
+		Expect(func() { logger.Fatal("fatal") }).To(PanicWith(exited(1)))
+		Expect(buf.String()).To(Equal("ERROR <nil> error realm realm\nERROR <nil> fatal realm realm\n"))
+	})
+
+	It("flushes pending records", func() {
+		ctx.AddRule(logging.NewDeduplicationRule(logging.InfoLevel, time.Minute, realm))
+		logger := logging.DynamicLogger(ctx, realm)
+		logger.Info("loop")
+		logger.Info("loop")
+
+		Expect(func() { logger.Fatal("fatal") }).To(PanicWith(exited(1)))
+		Expect(buf.String()).To(Equal("V[3] loop realm realm\nV[3] loop realm realm repeated 1\nERROR <nil> fatal realm realm\n"))
+	})
+
+	It("flushes pending records of other message contexts", func() {
+		other := logging.NewRealm("other")
+		ctx.AddRule(logging.NewDeduplicationRule(logging.InfoLevel, time.Minute, realm))
+		ctx.Logger(realm).Info("loop")
+		ctx.Logger(realm).Info("loop")
+		ctx.Logger(realm).Info("loop")
+
+		Expect(func() { ctx.Logger(other).Fatal("fatal") }).To(PanicWith(exited(1)))
+		Expect(func() { ctx.LoggerFor(other).Panic("panic") }).To(PanicWith("panic"))
+		Expect(buf.String()).To(Equal("V[3] loop realm realm\nV[3] loop realm realm repeated 2\nERROR <nil> fatal realm other\nERROR <nil> panic realm other\n"))
+	})
+
+	It("panics", func() {
+		Expect(func() { ctx.Logger(realm).Panic("panic") }).To(PanicWith("panic"))
+		Expect(buf.String()).To(Equal("ERROR <nil> panic realm realm\n"))
+	})
+
+	It("emits fatal record for realms without rule", func() {
+		logger := ctx.LoggerFor(realm)
+		Expect(logger.Enabled(logging.ErrorLevel)).To(BeFalse())
+		logger.Error("error")
+
+		Expect(func() { logger.WithValues("key", "value").Fatal("fatal") }).To(PanicWith(exited(1)))
+		Expect(func() { logger.Panic("panic") }).To(PanicWith("panic"))
+		Expect(buf.String()).To(Equal("ERROR <nil> fatal realm realm key value\nERROR <nil> panic realm realm\n"))
+	})
+
+	It("exits with non-logging logger", func() {
+		Expect(func() { logging.NonLoggingLogger.Fatal("fatal") }).To(PanicWith(exited(1)))
+		Expect(func() { logging.NonLoggingLogger.Panic("panic") }).To(PanicWith("panic"))
+		Expect(buf.String()).To(Equal(""))
+	})
+})
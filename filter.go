@@ -0,0 +1,343 @@
+/*
+ * Copyright 2023 Mandelsoft. All rights reserved.
+ *  This file is licensed under the Apache Software License, v. 2 except as noted
+ *  otherwise in the LICENSE file
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+package logging
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/go-logr/logr"
+)
+
+// Record describes a log record passed to a Filter at emission time.
+type Record struct {
+	// Level is the level of the record. Error records use the ErrorLevel.
+	Level int
+	// Message is the message text.
+	Message string
+	// Error is the error passed with an error record.
+	Error error
+	// Values are the key/value pairs of the record including
+	// the values bound to the logger, like the realm.
+	Values []interface{}
+}
+
+// Value returns the value for a key and whether the key is present.
+// If a key is given multiple times, the last value is used.
+func (r *Record) Value(key string) (interface{}, bool) {
+	for i := len(r.Values) - 2; i >= 0; i -= 2 {
+		if k, ok := r.Values[i].(string); ok && k == key {
+			return r.Values[i+1], true
+		}
+	}
+	return nil, false
+}
+
+// Filter is applied to log records at emission time, after the
+// activation of the logger has been checked. It may change the
+// level of the record. If it returns false, the record is dropped.
+//
+// Filters are configured for a logging context (see Context.AddFilter).
+// In contrast to rules, which are evaluated once for a message context,
+// filters are able to inspect the message text and the key/value pairs
+// of every record.
+type Filter interface {
+	Apply(r *Record) bool
+}
+
+// RecordCondition is a condition for a log record used by a FilterRule.
+type RecordCondition interface {
+	MatchRecord(r *Record) bool
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// FilterRule is a Filter dropping log records or changing their level,
+// if they match all given conditions.
+type FilterRule struct {
+	level      int
+	conditions []RecordCondition
+}
+
+var _ Filter = (*FilterRule)(nil)
+
+// NewDropFilter provides a filter dropping all log records
+// matching all given conditions.
+func NewDropFilter(cond ...RecordCondition) Filter {
+	return &FilterRule{level: None, conditions: cond}
+}
+
+// NewLevelFilter provides a filter changing the level of all log records
+// matching all given conditions. A record with a raised level is
+// emitted at the new level. A record with a lowered level is only
+// emitted, if the new level is enabled for the logger.
+// The level None drops the record.
+func NewLevelFilter(level int, cond ...RecordCondition) Filter {
+	return &FilterRule{level: level, conditions: cond}
+}
+
+func (f *FilterRule) Apply(r *Record) bool {
+	for _, c := range f.conditions {
+		if !c.MatchRecord(r) {
+			return true
+		}
+	}
+	if f.level <= None {
+		return false
+	}
+	r.Level = f.level
+	return true
+}
+
+// Level returns the new level for matching records.
+// The level None indicates a drop filter.
+func (f *FilterRule) Level() int {
+	return f.level
+}
+
+func (f *FilterRule) Conditions() []RecordCondition {
+	return sliceCopy(f.conditions)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// MessageGlob matches the message text against a glob pattern.
+// The character * matches any sequence of characters and ?
+// matches a single character.
+type MessageGlob struct {
+	pattern string
+	expr    *regexp.Regexp
+}
+
+var _ RecordCondition = (*MessageGlob)(nil)
+
+func NewMessageGlob(pattern string) RecordCondition {
+	return &MessageGlob{pattern: pattern, expr: globExpr(pattern)}
+}
+
+func (c *MessageGlob) Pattern() string {
+	return c.pattern
+}
+
+func (c *MessageGlob) MatchRecord(r *Record) bool {
+	return c.expr.MatchString(r.Message)
+}
+
+// MessageRegex matches the message text against a regular expression.
+// The expression matches, if it matches a part of the message text.
+type MessageRegex struct {
+	expr *regexp.Regexp
+}
+
+var _ RecordCondition = (*MessageRegex)(nil)
+
+func NewMessageRegex(expr string) (RecordCondition, error) {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	return &MessageRegex{expr: re}, nil
+}
+
+func (c *MessageRegex) Expression() string {
+	return c.expr.String()
+}
+
+func (c *MessageRegex) MatchRecord(r *Record) bool {
+	return c.expr.MatchString(r.Message)
+}
+
+// KeyPresent matches records with a value for the given key.
+type KeyPresent string
+
+var _ RecordCondition = KeyPresent("")
+
+func NewKeyPresent(key string) RecordCondition {
+	return KeyPresent(key)
+}
+
+func (c KeyPresent) Key() string {
+	return string(c)
+}
+
+func (c KeyPresent) MatchRecord(r *Record) bool {
+	_, ok := r.Value(string(c))
+	return ok
+}
+
+// KeyValueMatch matches records with a dedicated value for a key.
+// Values are compared by their string representation, so that
+// configured values match values of different types, for example
+// 200 matches an int value 200. If the value is a ValueMatcher,
+// it is used to match the value of the record.
+type KeyValueMatch struct {
+	key   string
+	value interface{}
+}
+
+var _ RecordCondition = (*KeyValueMatch)(nil)
+
+func NewKeyValueMatch(key string, value interface{}) RecordCondition {
+	return &KeyValueMatch{key: key, value: value}
+}
+
+func (c *KeyValueMatch) Key() string {
+	return c.key
+}
+
+func (c *KeyValueMatch) Value() interface{} {
+	return c.value
+}
+
+func (c *KeyValueMatch) MatchRecord(r *Record) bool {
+	v, ok := r.Value(c.key)
+	if !ok {
+		return false
+	}
+	if m, ok := c.value.(ValueMatcher); ok {
+		return m.MatchValue(v)
+	}
+	return fmt.Sprint(v) == fmt.Sprint(c.value)
+}
+
+// LevelMatch matches records with one of the given levels.
+type LevelMatch struct {
+	levels []int
+}
+
+var _ RecordCondition = (*LevelMatch)(nil)
+
+func NewLevelMatch(levels ...int) RecordCondition {
+	return &LevelMatch{levels: levels}
+}
+
+func (c *LevelMatch) Levels() []int {
+	return sliceCopy(c.levels)
+}
+
+func (c *LevelMatch) MatchRecord(r *Record) bool {
+	for _, l := range c.levels {
+		if r.Level == l {
+			return true
+		}
+	}
+	return false
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// filtered applies filters to the records issued by a logger.
+// Only loggers provided by this library can be filtered.
+func filtered(l Logger, filters []Filter) Logger {
+	if len(filters) == 0 {
+		return l
+	}
+	if ll, ok := l.(*logger); ok {
+		return &logger{&filterSink{filters: filters, sink: withCallDepth(ll.sink, 1)}, ll.ctx}
+	}
+	return l
+}
+
+type filterSink struct {
+	filters []Filter
+	sink    logr.LogSink
+	values  []interface{}
+}
+
+var _ logr.CallDepthLogSink = (*filterSink)(nil)
+var _ filteringSink = (*filterSink)(nil)
+
+func (s *filterSink) Init(info logr.RuntimeInfo) {
+}
+
+func (s *filterSink) Enabled(level int) bool {
+	return s.sink.Enabled(level)
+}
+
+// Info and Error emit the record directly to keep the call depth
+// of the wrapped sink.
+
+func (s *filterSink) Info(level int, msg string, keysAndValues ...interface{}) {
+	r := &Record{Level: level, Message: msg, Values: sliceAppend(s.values, keysAndValues...)}
+	if !s.apply(r) {
+		return
+	}
+	if r.Level <= ErrorLevel {
+		s.sink.Error(nil, r.Message, keysAndValues...)
+	} else {
+		s.sink.Info(r.Level, r.Message, keysAndValues...)
+	}
+}
+
+func (s *filterSink) Error(err error, msg string, keysAndValues ...interface{}) {
+	r := &Record{Level: ErrorLevel, Message: msg, Error: err, Values: sliceAppend(s.values, keysAndValues...)}
+	if !s.apply(r) {
+		return
+	}
+	if r.Level <= ErrorLevel {
+		s.sink.Error(err, r.Message, keysAndValues...)
+	} else {
+		if err != nil {
+			keysAndValues = append(keysAndValues[:len(keysAndValues):len(keysAndValues)], "error", err)
+		}
+		s.sink.Info(r.Level, r.Message, keysAndValues...)
+	}
+}
+
+// apply applies the filters and checks the activation
+// for a changed level.
+func (s *filterSink) apply(r *Record) bool {
+	level := r.Level
+	for _, f := range s.filters {
+		if !f.Apply(r) {
+			return false
+		}
+	}
+	return r.Level == level || r.Level <= ErrorLevel || s.sink.Enabled(r.Level)
+}
+
+func (s *filterSink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	n := *s
+	n.sink = s.sink.WithValues(keysAndValues...)
+	n.values = sliceAppend(s.values, keysAndValues...)
+	return &n
+}
+
+func (s *filterSink) WithName(name string) logr.LogSink {
+	n := *s
+	n.sink = s.sink.WithName(name)
+	return &n
+}
+
+func (s *filterSink) WithCallDepth(depth int) logr.LogSink {
+	n := *s
+	n.sink = withCallDepth(s.sink, depth)
+	return &n
+}
+
+func (s *filterSink) Unwrap() logr.LogSink {
+	return s.sink
+}
+
+func (s *filterSink) unfiltered() logr.LogSink {
+	if f, ok := s.sink.(filteringSink); ok {
+		// the frame of this sink is skipped, also.
+		return withCallDepth(f.unfiltered(), -1)
+	}
+	return s.sink
+}
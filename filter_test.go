@@ -0,0 +1,139 @@
+/*
+ * Copyright 2023 Mandelsoft. All rights reserved.
+ *  This file is licensed under the Apache Software License, v. 2 except as noted
+ *  otherwise in the LICENSE file
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+package logging_test
+
+import (
+	"bytes"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/tonglil/buflogr"
+
+	"github.com/mandelsoft/logging"
+)
+
+var _ = Describe("filters", func() {
+	var buf bytes.Buffer
+	var ctx logging.Context
+
+	realm := logging.NewRealm("realm")
+
+	BeforeEach(func() {
+		buf.Reset()
+		ctx = logging.New(buflogr.NewWithBuffer(&buf))
+	})
+
+	It("drops records by key value", func() {
+		ctx.AddFilter(logging.NewDropFilter(logging.NewLevelMatch(logging.InfoLevel), logging.NewKeyValueMatch("path", "/healthz")))
+		logger := ctx.Logger(realm)
+		logger.Info("request", "path", "/healthz")
+		logger.Info("request", "path", "/api")
+		logger.Warn("request", "path", "/healthz")
+		logger.WithValues("path", "/healthz").Info("bound")
+		Expect(buf.String()).To(Equal("V[3] request realm realm path /api\nV[2] request realm realm path /healthz\n"))
+	})
+
+	It("matches message text", func() {
+		re, err := logging.NewMessageRegex("^conn(ect)?ion")
+		Expect(err).To(Succeed())
+		ctx.AddFilter(logging.NewDropFilter(logging.NewMessageGlob("GET /health*")), logging.NewDropFilter(re))
+		logger := ctx.Logger()
+		logger.Info("GET /healthz")
+		logger.Info("GET /api")
+		logger.Info("connection closed")
+		logger.Info("new connection")
+		Expect(buf.String()).To(Equal("V[3] GET /api\nV[3] new connection\n"))
+
+		_, err = logging.NewMessageRegex("(")
+		Expect(err).NotTo(Succeed())
+	})
+
+	It("matches key presence and realm", func() {
+		ctx.AddFilter(logging.NewDropFilter(logging.NewKeyPresent("secret")))
+		ctx.AddFilter(logging.NewDropFilter(logging.NewKeyValueMatch(logging.FieldKeyRealm, "realm")))
+		ctx.Logger().Info("test", "secret", "x")
+		ctx.Logger(realm).Info("test")
+		ctx.Logger().Info("test", "status", 200)
+		Expect(buf.String()).To(Equal("V[3] test status 200\n"))
+	})
+
+	It("changes levels", func() {
+		ctx.SetDefaultLevel(logging.DebugLevel)
+		ctx.AddFilter(logging.NewLevelFilter(logging.WarnLevel, logging.NewMessageGlob("*refused*")))
+		ctx.AddFilter(logging.NewLevelFilter(logging.TraceLevel, logging.NewMessageGlob("noise*")))
+		ctx.AddFilter(logging.NewLevelFilter(logging.InfoLevel, logging.NewMessageGlob("expected error")))
+		logger := ctx.Logger()
+		logger.Debug("connection refused")
+		logger.Info("noise")
+		logger.LogError(fmt.Errorf("failed"), "expected error")
+		Expect(buf.String()).To(Equal("V[2] connection refused\nV[3] expected error error failed\n"))
+
+		buf.Reset()
+		ctx.SetDefaultLevel(logging.TraceLevel)
+		ctx.Logger().Info("noise")
+		Expect(buf.String()).To(Equal("V[5] noise\n"))
+	})
+
+	It("applies filters of base contexts", func() {
+		nested := logging.NewWithBase(ctx)
+		ctx.AddFilter(logging.NewDropFilter(logging.NewMessageGlob("base")))
+		nested.AddFilter(logging.NewDropFilter(logging.NewMessageGlob("nested")))
+		Expect(len(nested.Filters())).To(Equal(1))
+
+		for _, m := range []string{"base", "nested", "other"} {
+			nested.Logger().Info(m)
+			ctx.Logger().Info(m)
+		}
+		Expect(buf.String()).To(Equal("V[3] nested\nV[3] other\nV[3] other\n"))
+	})
+
+	It("applies filters to dynamic loggers", func() {
+		logger := logging.DynamicLogger(ctx, realm)
+		logger.Info("test")
+		ctx.AddFilter(logging.NewDropFilter(logging.NewMessageGlob("test")))
+		logger.Info("test")
+		ctx.SetFilters()
+		logger.Info("test")
+		Expect(buf.String()).To(Equal("V[3] test realm realm\nV[3] test realm realm\n"))
+	})
+
+	It("does not wrap loggers without filters", func() {
+		Expect(fmt.Sprintf("%T", ctx.Logger().V(0).GetSink())).NotTo(ContainSubstring("filter"))
+		ctx.AddFilter(logging.NewDropFilter(logging.NewMessageGlob("test")))
+		Expect(fmt.Sprintf("%T", ctx.Logger().V(0).GetSink())).To(ContainSubstring("filter"))
+	})
+
+	It("does not filter fatal records", func() {
+		old := logging.SetExitHook(func(code int) {})
+		defer logging.SetExitHook(old)
+
+		ctx.AddFilter(logging.NewDropFilter(logging.NewMessageGlob("fatal")))
+		ctx.Logger().Fatal("fatal")
+		Expect(buf.String()).To(Equal("ERROR <nil> fatal\n"))
+	})
+
+	It("notifies filter changes", func() {
+		var events []logging.ChangeEvent
+		defer ctx.Watch(func(e logging.ChangeEvent) { events = append(events, e) })()
+		ctx.AddFilter(logging.NewDropFilter())
+		Expect(len(events)).To(Equal(1))
+		Expect(events[0].Type).To(Equal(logging.ChangeFilters))
+	})
+})
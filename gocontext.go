@@ -0,0 +1,70 @@
+/*
+ * Copyright 2023 Mandelsoft. All rights reserved.
+ *  This file is licensed under the Apache Software License, v. 2 except as noted
+ *  otherwise in the LICENSE file
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+package logging
+
+import (
+	gocontext "context"
+)
+
+type attributionKey struct{}
+
+// NewContextWithAttribution returns a copy of the given Go context
+// carrying the AttributionContext of the given provider.
+// It can be retrieved again with FromContext, for example
+// by library code deep in the call chain.
+func NewContextWithAttribution(ctx gocontext.Context, ac AttributionContextProvider) gocontext.Context {
+	return gocontext.WithValue(ctx, attributionKey{}, ac.AttributionContext())
+}
+
+// FromContext returns the AttributionContext stored in the given Go context.
+// If there is none, an AttributionContext for the DefaultContext
+// is returned.
+func FromContext(ctx gocontext.Context) AttributionContext {
+	if ctx != nil {
+		if ac, ok := ctx.Value(attributionKey{}).(AttributionContext); ok {
+			return ac
+		}
+	}
+	return DefaultContext().AttributionContext()
+}
+
+// LoggerFromContext returns the effective Logger for the AttributionContext
+// found in the given Go context and the given message context.
+func LoggerFromContext(ctx gocontext.Context, messageContext ...MessageContext) Logger {
+	return FromContext(ctx).Logger(messageContext...)
+}
+
+// DynamicLoggerFromContext returns an UnboundLogger for the AttributionContext
+// found in the given Go context and the given message context.
+func DynamicLoggerFromContext(ctx gocontext.Context, messageContext ...MessageContext) UnboundLogger {
+	return DynamicLogger(FromContext(ctx), messageContext...)
+}
+
+// ContextWithMessageContext returns a copy of the given Go context
+// with the AttributionContext found in the given Go context enriched
+// by the given message context.
+func ContextWithMessageContext(ctx gocontext.Context, messageContext ...MessageContext) gocontext.Context {
+	return NewContextWithAttribution(ctx, FromContext(ctx).WithContext(messageContext...))
+}
+
+// ContextWithValues returns a copy of the given Go context
+// with the AttributionContext found in the given Go context enriched
+// by the given key/value pairs.
+func ContextWithValues(ctx gocontext.Context, keypairs ...interface{}) gocontext.Context {
+	return NewContextWithAttribution(ctx, FromContext(ctx).WithValues(keypairs...))
+}
@@ -0,0 +1,79 @@
+/*
+ * Copyright 2023 Mandelsoft. All rights reserved.
+ *  This file is licensed under the Apache Software License, v. 2 except as noted
+ *  otherwise in the LICENSE file
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+package logging_test
+
+import (
+	"bytes"
+	gocontext "context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/tonglil/buflogr"
+
+	"github.com/mandelsoft/logging"
+)
+
+var _ = Describe("go context", func() {
+	var buf bytes.Buffer
+	var ctx logging.Context
+
+	realm := logging.NewRealm("realm")
+	attr := logging.NewAttribute("attr", "value")
+
+	BeforeEach(func() {
+		buf.Reset()
+		ctx = logging.New(buflogr.NewWithBuffer(&buf))
+	})
+
+	It("propagates attribution context", func() {
+		actx := logging.NewAttributionContext(ctx, realm).WithValues("key", "value")
+		gctx := logging.NewContextWithAttribution(gocontext.Background(), actx)
+
+		Expect(logging.FromContext(gctx)).To(BeIdenticalTo(actx))
+		logging.LoggerFromContext(gctx).Info("test")
+		Expect(buf.String()).To(Equal("V[3] test realm realm key value\n"))
+	})
+
+	It("enriches attribution context", func() {
+		gctx := logging.NewContextWithAttribution(gocontext.Background(), ctx)
+		gctx = logging.ContextWithMessageContext(gctx, realm, attr)
+		gctx = logging.ContextWithValues(gctx, "key", "value")
+
+		logging.LoggerFromContext(gctx, logging.NewName("name")).Info("test")
+		Expect(buf.String()).To(Equal("V[3] name test realm realm attr value key value\n"))
+	})
+
+	It("provides dynamic loggers", func() {
+		gctx := logging.NewContextWithAttribution(gocontext.Background(), ctx.AttributionContext().WithContext(realm))
+		logger := logging.DynamicLoggerFromContext(gctx)
+		logger.Debug("debug")
+		ctx.AddRule(logging.NewConditionRule(logging.DebugLevel, realm))
+		logger.Debug("debug")
+		Expect(buf.String()).To(Equal("V[4] debug realm realm\n"))
+	})
+
+	It("falls back to default context", func() {
+		defer logging.PreserveDefaultContext()()
+		logging.SetDefaultContext(ctx)
+
+		Expect(logging.FromContext(gocontext.Background()).LoggingContext()).To(BeIdenticalTo(ctx))
+		logging.LoggerFromContext(gocontext.Background(), realm).Info("test")
+		Expect(buf.String()).To(Equal("V[3] test realm realm\n"))
+	})
+})
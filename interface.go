@@ -19,9 +19,7 @@
 package logging
 
 import (
-	"fmt"
-	"strconv"
-	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 )
@@ -49,49 +47,6 @@ const (
 	TraceLevel
 )
 
-// ParseLevel maps a string representation of a log level to
-// it internal value. It also accepts the representation as
-// number.
-func ParseLevel(s string) (int, error) {
-	switch strings.ToLower(strings.TrimSpace(s)) {
-	case "error":
-		return ErrorLevel, nil
-	case "warn":
-		return WarnLevel, nil
-	case "info":
-		return InfoLevel, nil
-	case "debug":
-		return DebugLevel, nil
-	case "trace":
-		return TraceLevel, nil
-	default:
-		v, err := strconv.ParseInt(s, 10, 32)
-		if err != nil || v < 0 {
-			return 0, fmt.Errorf("invalid log level %q", s)
-		}
-		return int(v), nil
-	}
-}
-
-// LevelName returns the logical name of a log level.
-// It can be parsed again with ParseLevel.
-func LevelName(l int) string {
-	switch l {
-	case ErrorLevel:
-		return "Error"
-	case WarnLevel:
-		return "Warn"
-	case InfoLevel:
-		return "Info"
-	case DebugLevel:
-		return "Debug"
-	case TraceLevel:
-		return "Trace"
-	default:
-		return fmt.Sprintf("%d", l)
-	}
-}
-
 // Logger is the main logging interface.
 // It is used to issue log messages.
 // Additionally, it provides methods
@@ -110,6 +65,16 @@ type Logger interface {
 	Debug(msg string, keypairs ...interface{})
 	// Trace logs an trace message.
 	Trace(msg string, keypairs ...interface{})
+	// Log logs a message for the given level. The ErrorLevel is logged
+	// as error message, the level None (or less) is never logged.
+	Log(level int, msg string, keypairs ...interface{})
+	// Fatal logs an error message regardless of the activation of the
+	// logger, flushes pending log records and terminates the process
+	// with exit code 1 using the exit hook (see SetExitHook).
+	Fatal(msg string, keypairs ...interface{})
+	// Panic logs an error message regardless of the activation of the
+	// logger, flushes pending log records and panics with the message.
+	Panic(msg string, keypairs ...interface{})
 
 	// NewName return a new logger with an extended name,
 	// but the same logging activation.
@@ -117,6 +82,12 @@ type Logger interface {
 	// WithValues return a new logger with more standard key/value pairs,
 	// but the same logging activation.
 	WithValues(keypairs ...interface{}) Logger
+	// WithCallDepth returns a new logger, which reports the caller
+	// the given number of stack frames above the caller of the logging
+	// methods. This can be used by helper functions issuing log
+	// records on behalf of their caller. It is supported by sinks
+	// implementing logr.CallDepthLogSink.
+	WithCallDepth(depth int) Logger
 
 	// Enabled check whether the logger is active for a dedicated level.
 	Enabled(level int) bool
@@ -166,7 +137,12 @@ type Condition interface {
 }
 
 // Rule matches a given message context and returns
-// an appropriate logger
+// an appropriate logger.
+// The evaluation result for a message context is cached by
+// the logging context until the context tree is modified.
+// Therefore, the result of a rule must only depend on the
+// message context. Rules depending on other state must modify
+// the context (see Updater) whenever this state changes.
 type Rule interface {
 	Match(SinkFunc, ...MessageContext) Logger
 }
@@ -182,6 +158,43 @@ type UpdatableRule interface {
 	MatchRule(Rule) bool
 }
 
+// RuleEntry describes a rule of the rule set of a logging context
+// together with its identity.
+type RuleEntry struct {
+	// Id is the identity of the rule in its logging context.
+	// It is the name given when adding the rule with AddNamedRule, or an
+	// id generated by the context.
+	Id string
+	// Rule is the rule itself.
+	Rule Rule
+
+	named bool
+}
+
+// ContextRule is the optional interface for a rule requiring
+// access to the logging context owning the rule for its evaluation.
+// If implemented, it is used instead of the Match method by the
+// owning logging context.
+type ContextRule interface {
+	Rule
+	MatchWithContext(ctx Context, base SinkFunc, messageContext ...MessageContext) Logger
+}
+
+// TemporaryRule is the optional interface for a rule, whose
+// activation state depends on the time.
+// A logging context uses it to remove expired rules
+// and to update provided dynamic loggers, when the
+// activation state changes.
+type TemporaryRule interface {
+	Rule
+	// NextChange returns the next point in time after now,
+	// the activation state of the rule changes.
+	// The zero time is returned, if there is no further change.
+	NextChange(now time.Time) time.Time
+	// Expired returns true, if the rule will never match again.
+	Expired(now time.Time) bool
+}
+
 // ContextProvider is able to provide access to a logging context.
 type ContextProvider interface {
 	LoggingContext() Context
@@ -217,7 +230,9 @@ type LoggerProvider interface {
 	// LoggerFor provides a logger according to rules for a dedicated message
 	// context. There is no default level and no log context involved,
 	// only the base logr sink is used.
-	// If no rule matches the [NonLoggingLogger] is returned.
+	// If no rule matches, a logger is returned, which behaves like the
+	// [NonLoggingLogger], but still emits fatal and panic records to the
+	// base logr sink.
 	LoggerFor(messageContext ...MessageContext) Logger
 }
 
@@ -243,6 +258,10 @@ type AttributionContext interface {
 
 	// Match evaluates a condition against the message context.
 	Match(cond Condition) bool
+
+	// Explain describes how the effective logger for the given message
+	// context is determined by the logging context.
+	Explain(messageContext ...MessageContext) *Explanation
 }
 
 // Context describes the interface of a logging context.
@@ -264,6 +283,9 @@ type Context interface {
 	// These may be locally defined rules, or, in case of a nested logger,
 	// rules of the base context, also.
 	GetDefaultLevel() int
+	// DefaultLevel returns the default log level set at this context.
+	// For a nested context without own default level -1 is returned.
+	DefaultLevel() int
 	// GetDefaultLogger return the effective default logger used if no rule matches
 	// a message context.
 	GetDefaultLogger() Logger
@@ -278,17 +300,77 @@ type Context interface {
 	// Although the error output is filtered by this log level by the
 	// original sink, error level output, if enabled, is passed as Error to the sink.
 	SetBaseLogger(logger logr.Logger, plain ...bool)
+	// SetNamedSink registers an additional sink under a dedicated name,
+	// which can be used as target of routing rules (see NewNamedRoutingRule).
+	// Like for SetBaseLogger, the base log level is taken from the
+	// given logger, if the optional parameter plain is not set to true.
+	SetNamedSink(name string, logger logr.Logger, plain ...bool)
+	// GetNamedSink returns the sink registered for a name at this
+	// context or its base contexts. If there is no such sink, nil
+	// is returned.
+	GetNamedSink(name string) logr.LogSink
 
 	// AddRule adds a rule to the actual context.
 	// It may decide to supersede already existing rules. In
 	// such case all matching rules (interface UpdatableRule)
 	// will be deleted from the active rule set.
 	AddRule(...Rule)
+	// AddNamedRule adds a rule with a dedicated name used as rule id.
+	// An already existing rule with this id is replaced and the rule
+	// is placed at the top of the rule set. Like AddRule, it may supersede
+	// already existing matching rules.
+	AddNamedRule(id string, rule Rule)
+	// Rules returns the actual rule set in evaluation order, which is the
+	// reverse order of their definition.
+	// Rules inherited from base contexts are not included.
+	Rules() []RuleEntry
+	// RemoveRule removes the rule with the given id from the actual rule set.
+	// It returns false, if there is no such rule.
+	RemoveRule(id string) bool
+	// ReplaceRule replaces the rule with the given id keeping its
+	// position in the rule set.
+	// It returns false, if there is no such rule.
+	ReplaceRule(id string, rule Rule) bool
 	// ResetRules deletes the actual rule set.
 	ResetRules()
+	// ReplaceRules atomically replaces the default level and the complete
+	// rule set. The rules are added like with AddRule.
+	// A negative level resets the default level to its initial state,
+	// which is InfoLevel for a root context and the level inherited from
+	// the base context for a nested context.
+	ReplaceRules(level int, rules ...Rule)
+	// ReplaceConfiguration atomically replaces the default level, the
+	// complete rule set and the filters of this context like ReplaceRules
+	// and SetFilters, but with a single modification visible to concurrently
+	// used loggers.
+	ReplaceConfiguration(level int, rules []Rule, filters []Filter)
 	// AddRulesTo add the actual rules to another logging context.
 	AddRulesTo(ctx Context)
 
+	// AddFilter adds filters applied to all log records at emission
+	// time (see Filter). Filters of base contexts are applied after the
+	// filters of a nested context.
+	AddFilter(filters ...Filter)
+	// SetFilters replaces the filters of this context.
+	SetFilters(filters ...Filter)
+	// Filters returns the filters of this context in the order of their
+	// application. Filters inherited from base contexts are not included.
+	Filters() []Filter
+
+	// Snapshot captures the actual configuration of this context
+	// (default level, rules, base logger, technical writer and named sinks).
+	Snapshot() Snapshot
+	// Restore restores the configuration captured by a snapshot
+	// of this context. Loggers provided by this context or nested
+	// contexts are updated like for any other modification.
+	Restore(Snapshot) error
+
+	// Watch registers a function called for every configuration change
+	// (rules, default level, base logger or named sinks) of this context
+	// or one of its base contexts.
+	// The registration is removed by calling the returned function.
+	Watch(func(ChangeEvent)) func()
+
 	// WithContext provides a new logging Context enriched by the given standard
 	// message context
 	WithContext(messageContext ...MessageContext) Context
@@ -300,6 +382,12 @@ type Context interface {
 	// Match evaluates a condition against the message context.
 	Match(cond Condition) bool
 
+	// Explain describes how the effective logger for the given message
+	// context is determined. It reports the matching rule together with
+	// the context in the context tree owning it, the effective level and
+	// the rules skipped during the evaluation.
+	Explain(messageContext ...MessageContext) *Explanation
+
 	// Tree provides an interface for the context intended for
 	// context implementations to work together in a context tree.
 	Tree() ContextSupport
@@ -347,6 +435,8 @@ var _ Condition = RealmPrefix("")
 // as logging condition or message context.
 // If used as message context it will be attached to
 // the logging message as additional value.
+// If used as condition, the value may be a ValueMatcher
+// to match a set of values.
 type Attribute interface {
 	Condition
 	Attacher
@@ -363,6 +453,15 @@ type Tag = tag
 
 var _ Condition = Tag("")
 
+// TagPrefix is used as logging condition to
+// match a tag hierarchy. It matches tags of the
+// message context with the given name or a name
+// starting with the given name followed by
+// a dot or slash.
+type TagPrefix = tagprefix
+
+var _ Condition = TagPrefix("")
+
 // Name is a simple string value, which can be used as
 // message context.
 // It will not be attached to the logger's name.
@@ -0,0 +1,77 @@
+/*
+ * Copyright 2023 Mandelsoft. All rights reserved.
+ *  This file is licensed under the Apache Software License, v. 2 except as noted
+ *  otherwise in the LICENSE file
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+package logging_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/tonglil/buflogr"
+
+	"github.com/mandelsoft/logging"
+	"github.com/mandelsoft/logging/utils"
+)
+
+var _ = Describe("lazy values", func() {
+	var buf bytes.Buffer
+	var ctx logging.Context
+	var calls int
+
+	value := func() interface{} {
+		calls++
+		return "expensive"
+	}
+
+	BeforeEach(func() {
+		buf.Reset()
+		calls = 0
+		ctx = logging.New(buflogr.NewWithBuffer(&buf))
+	})
+
+	It("does not evaluate values for disabled levels", func() {
+		logger := ctx.Logger()
+		logger.Debug("debug", "key", logging.Lazy(value))
+		logger.Log(logging.TraceLevel, "trace", logging.LazyKeyValue("key", value))
+		Expect(calls).To(Equal(0))
+		Expect(buf.String()).To(Equal(""))
+	})
+
+	It("evaluates values for enabled levels", func() {
+		logger := ctx.Logger()
+		logger.Info("info", "key", logging.Lazy(value))
+		logger.Error("error", logging.LazyKeyValue("key", value))
+		Expect(calls).To(Equal(2))
+		Expect(buf.String()).To(Equal("V[3] info key expensive\nERROR <nil> error key expensive\n"))
+	})
+
+	It("evaluates values for dynamic loggers", func() {
+		logger := logging.DynamicLogger(ctx)
+		logger.Debug("debug", "key", logging.Lazy(value))
+		Expect(calls).To(Equal(0))
+		logger.Warn("warn", "key", logging.Lazy(value))
+		Expect(calls).To(Equal(1))
+		Expect(buf.String()).To(Equal("V[2] warn key expensive\n"))
+	})
+
+	It("omits ignored lazy values", func() {
+		ctx.Logger().Info("info", "key", logging.Lazy(func() interface{} { return utils.Ignore }), "other", "value")
+		Expect(buf.String()).To(Equal("V[3] info other value\n"))
+	})
+})
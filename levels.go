@@ -0,0 +1,134 @@
+/*
+ * Copyright 2023 Mandelsoft. All rights reserved.
+ *  This file is licensed under the Apache Software License, v. 2 except as noted
+ *  otherwise in the LICENSE file
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package logging
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mandelsoft/logging/logrusfmt"
+	"github.com/mandelsoft/logging/logrusr"
+)
+
+// levels is the registry of level names.
+var levels = &levelRegistry{
+	levels: map[string]int{},
+	names:  map[int]string{},
+}
+
+func init() {
+	DefineLevel("None", None)
+	DefineLevel("off", None)
+	DefineLevel("Error", ErrorLevel)
+	DefineLevel("Warn", WarnLevel)
+	DefineLevel("Info", InfoLevel)
+	DefineLevel("Debug", DebugLevel)
+	DefineLevel("Trace", TraceLevel)
+}
+
+type levelRegistry struct {
+	lock   sync.RWMutex
+	levels map[string]int
+	names  map[int]string
+}
+
+// DefineLevel defines a name for a numeric log level.
+// Names are case-insensitive. A level may have multiple
+// names, the first defined name is used by LevelName to
+// represent the level, all names are accepted by ParseLevel.
+// This affects the level fields of logging configurations, also.
+// The first name defined for a level beyond TraceLevel is used
+// by the formatters of package logrusfmt to render the level.
+func DefineLevel(name string, level int) error {
+	key := strings.ToLower(strings.TrimSpace(name))
+	if key == "" {
+		return fmt.Errorf("level name missing")
+	}
+	if _, err := strconv.ParseInt(key, 10, 32); err == nil {
+		return fmt.Errorf("numeric level name %q not possible", name)
+	}
+	if level < 0 {
+		return fmt.Errorf("invalid log level %d", level)
+	}
+
+	levels.lock.Lock()
+	defer levels.lock.Unlock()
+
+	if l, ok := levels.levels[key]; ok {
+		if l != level {
+			return fmt.Errorf("level name %q already defined for level %d", name, l)
+		}
+		return nil
+	}
+	levels.levels[key] = level
+	if _, ok := levels.names[level]; !ok {
+		levels.names[level] = name
+		if level > TraceLevel {
+			logrusfmt.SetLevelName(logrusr.LogrusLevel(level), name)
+		}
+	}
+	return nil
+}
+
+// GetLevelDefinitions returns all defined level names
+// with their numeric levels.
+func GetLevelDefinitions() map[string]int {
+	levels.lock.RLock()
+	defer levels.lock.RUnlock()
+
+	r := map[string]int{}
+	for n, l := range levels.levels {
+		r[n] = l
+	}
+	return r
+}
+
+// ParseLevel maps a string representation of a log level to
+// it internal value. It accepts all level names (see DefineLevel)
+// and the representation as number.
+func ParseLevel(s string) (int, error) {
+	key := strings.ToLower(strings.TrimSpace(s))
+
+	levels.lock.RLock()
+	l, ok := levels.levels[key]
+	levels.lock.RUnlock()
+	if ok {
+		return l, nil
+	}
+
+	v, err := strconv.ParseInt(key, 10, 32)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("invalid log level %q", s)
+	}
+	return int(v), nil
+}
+
+// LevelName returns the logical name of a log level.
+// It can be parsed again with ParseLevel.
+func LevelName(l int) string {
+	levels.lock.RLock()
+	defer levels.lock.RUnlock()
+
+	if n, ok := levels.names[l]; ok {
+		return n
+	}
+	return fmt.Sprintf("%d", l)
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright 2023 Mandelsoft. All rights reserved.
+ *  This file is licensed under the Apache Software License, v. 2 except as noted
+ *  otherwise in the LICENSE file
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package logging_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/tonglil/buflogr"
+
+	"github.com/mandelsoft/logging"
+	"github.com/mandelsoft/logging/logrusl"
+)
+
+const ProtocolLevel = 7
+
+func init() {
+	logging.DefineLevel("proto", ProtocolLevel)
+	logging.DefineLevel("protocol", ProtocolLevel)
+	logging.DefineLevel("notice", logging.InfoLevel)
+}
+
+var _ = Describe("level names", func() {
+	var buf bytes.Buffer
+
+	BeforeEach(func() {
+		buf.Reset()
+	})
+
+	It("parses level names", func() {
+		Expect(logging.ParseLevel("none")).To(Equal(logging.None))
+		Expect(logging.ParseLevel("Off")).To(Equal(logging.None))
+		Expect(logging.ParseLevel("debug")).To(Equal(logging.DebugLevel))
+		Expect(logging.ParseLevel("notice")).To(Equal(logging.InfoLevel))
+		Expect(logging.ParseLevel("Proto")).To(Equal(ProtocolLevel))
+		Expect(logging.ParseLevel("protocol")).To(Equal(ProtocolLevel))
+		Expect(logging.ParseLevel("9")).To(Equal(9))
+		_, err := logging.ParseLevel("unknown")
+		Expect(err).To(MatchError(`invalid log level "unknown"`))
+	})
+
+	It("provides level names", func() {
+		Expect(logging.LevelName(logging.None)).To(Equal("None"))
+		Expect(logging.LevelName(logging.InfoLevel)).To(Equal("Info"))
+		Expect(logging.LevelName(ProtocolLevel)).To(Equal("proto"))
+		Expect(logging.LevelName(9)).To(Equal("9"))
+	})
+
+	It("rejects conflicting definitions", func() {
+		Expect(logging.DefineLevel("proto", ProtocolLevel)).To(Succeed())
+		Expect(logging.DefineLevel("proto", 8)).To(MatchError(`level name "proto" already defined for level 7`))
+		Expect(logging.DefineLevel("10", 10)).NotTo(Succeed())
+		Expect(logging.GetLevelDefinitions()).To(HaveKeyWithValue("protocol", ProtocolLevel))
+	})
+
+	It("logs with level", func() {
+		ctx := logging.New(buflogr.NewWithBuffer(&buf))
+		ctx.SetDefaultLevel(ProtocolLevel)
+		logger := ctx.Logger()
+		logger.Log(ProtocolLevel, "dump", "key", "value")
+		logger.Log(8, "hidden")
+		logger.Log(logging.ErrorLevel, "error")
+		Expect(buf.String()).To(Equal("V[7] dump key value\nERROR <nil> error\n"))
+	})
+
+	It("does not log level none", func() {
+		ctx := logging.New(buflogr.NewWithBuffer(&buf))
+		ctx.SetDefaultLevel(logging.TraceLevel)
+		off, err := logging.ParseLevel("off")
+		Expect(err).To(Succeed())
+
+		ctx.Logger().Log(off, "none")
+		ctx.Logger().Log(-1, "negative")
+		logging.DynamicLogger(ctx).Log(logging.None, "none")
+		Expect(buf.String()).To(Equal(""))
+	})
+
+	It("renders level names", func() {
+		ctx := logrusl.Human().WithWriter(&buf).New()
+		ctx.SetDefaultLevel(ProtocolLevel)
+		logging.DynamicLogger(ctx).Log(ProtocolLevel, "dump")
+		Expect(buf.String()).To(MatchRegexp(`.{25} proto   dump\n`))
+	})
+})
@@ -25,52 +25,99 @@ import (
 
 type logger struct {
 	sink logr.LogSink
+	// ctx is the logging context providing the logger, if any.
+	// It is flushed before emitting fatal and panic records.
+	ctx Context
 }
 
 var _ Logger = (*logger)(nil)
 
 func NewLogger(s logr.LogSink) Logger {
-	return &logger{s}
+	return &logger{sink: s}
 }
 
 func (l *logger) V(delta int) logr.Logger {
 	return logr.New(l.sink).V(delta)
 }
 
+// The logging methods call the sink directly to provide the same
+// call depth as a logr.Logger (see logr.CallDepthLogSink).
+// The level is checked before preparing the key/value pairs
+// to avoid the resolution of lazy values for disabled levels.
+
 func (l *logger) LogError(err error, msg string, keypairs ...interface{}) {
-	if l.Enabled(ErrorLevel) {
+	if l.sink.Enabled(ErrorLevel) {
 		l.sink.Error(err, msg, prepare(keypairs)...)
 	}
 }
 
 func (l *logger) Error(msg string, keypairs ...interface{}) {
-	if l.Enabled(ErrorLevel) {
+	if l.sink.Enabled(ErrorLevel) {
 		l.sink.Error(nil, msg, prepare(keypairs)...)
 	}
 }
 
 func (l *logger) Warn(msg string, keypairs ...interface{}) {
-	l.sink.Info(WarnLevel, msg, prepare(keypairs)...)
+	if l.sink.Enabled(WarnLevel) {
+		l.sink.Info(WarnLevel, msg, prepare(keypairs)...)
+	}
 }
 
 func (l *logger) Info(msg string, keypairs ...interface{}) {
-	l.sink.Info(InfoLevel, msg, prepare(keypairs)...)
+	if l.sink.Enabled(InfoLevel) {
+		l.sink.Info(InfoLevel, msg, prepare(keypairs)...)
+	}
 }
 
 func (l *logger) Debug(msg string, keypairs ...interface{}) {
-	l.sink.Info(DebugLevel, msg, prepare(keypairs)...)
+	if l.sink.Enabled(DebugLevel) {
+		l.sink.Info(DebugLevel, msg, prepare(keypairs)...)
+	}
 }
 
 func (l *logger) Trace(msg string, keypairs ...interface{}) {
-	l.sink.Info(TraceLevel, msg, prepare(keypairs)...)
+	if l.sink.Enabled(TraceLevel) {
+		l.sink.Info(TraceLevel, msg, prepare(keypairs)...)
+	}
+}
+
+func (l *logger) Log(level int, msg string, keypairs ...interface{}) {
+	if level <= None {
+		return
+	}
+	if level == ErrorLevel {
+		if l.sink.Enabled(ErrorLevel) {
+			l.sink.Error(nil, msg, prepare(keypairs)...)
+		}
+		return
+	}
+	if l.sink.Enabled(level) {
+		l.sink.Info(level, msg, prepare(keypairs)...)
+	}
+}
+
+func (l *logger) Fatal(msg string, keypairs ...interface{}) {
+	flushContext(l.ctx)
+	emitAlways(l.sink, msg, keypairs)
+	exit(1)
+}
+
+func (l *logger) Panic(msg string, keypairs ...interface{}) {
+	flushContext(l.ctx)
+	emitAlways(l.sink, msg, keypairs)
+	panic(msg)
 }
 
 func (l logger) WithName(name string) Logger {
-	return &logger{l.sink.WithName(name)}
+	return &logger{l.sink.WithName(name), l.ctx}
 }
 
 func (l logger) WithValues(keypairs ...interface{}) Logger {
-	return &logger{l.sink.WithValues(prepare(keypairs)...)}
+	return &logger{l.sink.WithValues(prepare(keypairs)...), l.ctx}
+}
+
+func (l logger) WithCallDepth(depth int) Logger {
+	return &logger{withCallDepth(l.sink, depth), l.ctx}
 }
 
 func (l logger) Enabled(level int) bool {
@@ -79,7 +126,12 @@ func (l logger) Enabled(level int) bool {
 
 ////////////////////////////////////////////////////////////////////////////////
 
-type nologger struct{}
+// nologger never logs regular records. If a sink is given,
+// fatal and panic records are still emitted to this sink.
+type nologger struct {
+	sink logr.LogSink
+	ctx  Context
+}
 
 // NonLoggingLogger is [Logger] which never logs anything.
 var NonLoggingLogger Logger = nologger{}
@@ -102,12 +154,44 @@ func (n nologger) Debug(msg string, keypairs ...interface{}) {
 func (n nologger) Trace(msg string, keypairs ...interface{}) {
 }
 
+func (n nologger) Log(level int, msg string, keypairs ...interface{}) {
+}
+
+func (n nologger) Fatal(msg string, keypairs ...interface{}) {
+	flushContext(n.ctx)
+	if n.sink != nil {
+		emitAlways(n.sink, msg, keypairs)
+	}
+	exit(1)
+}
+
+func (n nologger) Panic(msg string, keypairs ...interface{}) {
+	flushContext(n.ctx)
+	if n.sink != nil {
+		emitAlways(n.sink, msg, keypairs)
+	}
+	panic(msg)
+}
+
 func (n nologger) WithName(name string) Logger {
-	return n
+	if n.sink == nil {
+		return n
+	}
+	return nologger{n.sink.WithName(name), n.ctx}
 }
 
 func (n nologger) WithValues(keypairs ...interface{}) Logger {
-	return n
+	if n.sink == nil {
+		return n
+	}
+	return nologger{n.sink.WithValues(prepare(keypairs)...), n.ctx}
+}
+
+func (n nologger) WithCallDepth(depth int) Logger {
+	if n.sink == nil {
+		return n
+	}
+	return nologger{withCallDepth(n.sink, depth), n.ctx}
 }
 
 func (n nologger) Enabled(level int) bool {
@@ -178,7 +262,22 @@ func KeyValue(key string, value interface{}) *keyValue {
 	}
 }
 
-// prepare
+// Lazy provides a value for the argument list of logging methods, which
+// is determined by calling the given function only if the log record is
+// finally emitted. If the function returns utils.Ignore, the field
+// is omitted.
+// Values passed to WithValues are resolved immediately.
+func Lazy(f func() interface{}) utils.Lazy {
+	return utils.Lazy(f)
+}
+
+// LazyKeyValue provide a key/value pair for the argument list of logging
+// methods with a lazy value (see Lazy).
+func LazyKeyValue(key string, f func() interface{}) *keyValue {
+	return KeyValue(key, Lazy(f))
+}
+
+// prepare normalizes the key/value pairs and resolves lazy values.
 func prepare(keypairs []interface{}) []interface{} {
 	for i, e := range keypairs {
 		if i%2 == 0 {
@@ -189,6 +288,9 @@ func prepare(keypairs []interface{}) []interface{} {
 			if e == utils.Ignore {
 				return _prepare(keypairs)
 			}
+			if _, ok := e.(utils.Lazy); ok {
+				return _prepare(keypairs)
+			}
 		}
 	}
 	return keypairs
@@ -198,14 +300,14 @@ func _prepare(keypairs []interface{}) []interface{} {
 	var r []interface{}
 	for i := 0; i < len(keypairs); i += 2 {
 		if v, ok := keypairs[i].(keyvalue); ok {
-			if v.Value() != utils.Ignore {
-				r = append(r, v.Name(), v.Value())
+			if value := utils.Resolve(v.Value()); value != utils.Ignore {
+				r = append(r, v.Name(), value)
 			}
 			i--
 		} else {
 			if i+1 < len(keypairs) {
-				if keypairs[i+1] != utils.Ignore {
-					r = append(r, keypairs[i], keypairs[i+1])
+				if value := utils.Resolve(keypairs[i+1]); value != utils.Ignore {
+					r = append(r, keypairs[i], value)
 				}
 			} else {
 				r = append(r, keypairs[i]) // preserve the erroneous value
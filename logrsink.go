@@ -0,0 +1,124 @@
+/*
+ * Copyright 2023 Mandelsoft. All rights reserved.
+ *  This file is licensed under the Apache Software License, v. 2 except as noted
+ *  otherwise in the LICENSE file
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+package logging
+
+import (
+	"strings"
+
+	"github.com/go-logr/logr"
+)
+
+// NameMapping maps the names accumulated by the WithName calls
+// of a logr.Logger to message contexts used to evaluate the rules
+// of a logging context (see NewContextSink).
+type NameMapping func(names []string) []MessageContext
+
+// RealmMapping provides a NameMapping mapping the names to
+// a Realm below the given base realm by using the names
+// as realm segments. Additionally, the names are kept as
+// logger names.
+// For example, the names controller and foo are mapped to the realm
+// controller/foo, if no base realm is given.
+func RealmMapping(base string) NameMapping {
+	return func(names []string) []MessageContext {
+		var mctx []MessageContext
+		realm := strings.Join(sliceAppend([]string{base}, names...), "/")
+		realm = strings.Trim(realm, "/")
+		if realm != "" {
+			mctx = append(mctx, NewRealm(realm))
+		}
+		for _, n := range names {
+			mctx = append(mctx, NewName(n))
+		}
+		return mctx
+	}
+}
+
+type contextSink struct {
+	actx    AttributionContext
+	mapping NameMapping
+	names   []string
+	depth   int
+	logger  Logger
+}
+
+var _ logr.CallDepthLogSink = (*contextSink)(nil)
+
+// NewContextSink provides a logr.LogSink, which determines
+// the activation of log records by the rules of a logging context.
+// It can be used to pass a logr.Logger to code not aware of
+// this library. The names accumulated by calls to WithName are mapped to
+// message contexts by the given mapping (default is RealmMapping("")).
+// The V levels are mapped relative to the InfoLevel.
+// Like for an UnboundLogger, rule changes of the logging context are
+// reflected by the sink.
+func NewContextSink(ctxp AttributionContextProvider, mapping NameMapping) logr.LogSink {
+	if mapping == nil {
+		mapping = RealmMapping("")
+	}
+	s := &contextSink{
+		actx:    ctxp.AttributionContext(),
+		mapping: mapping,
+	}
+	return s.with(s.actx, nil, 0)
+}
+
+// NewLogr provides a logr.Logger based on a sink provided by
+// NewContextSink.
+func NewLogr(ctxp AttributionContextProvider, mapping NameMapping) logr.Logger {
+	return logr.New(NewContextSink(ctxp, mapping))
+}
+
+func (s *contextSink) with(actx AttributionContext, names []string, depth int) *contextSink {
+	return &contextSink{
+		actx:    actx,
+		mapping: s.mapping,
+		names:   names,
+		depth:   depth,
+		// +1 for the frame of this sink.
+		logger: DynamicLogger(actx, s.mapping(names)...).WithCallDepth(depth + 1),
+	}
+}
+
+func (s *contextSink) Init(info logr.RuntimeInfo) {
+	*s = *s.with(s.actx, s.names, info.CallDepth)
+}
+
+func (s *contextSink) Enabled(level int) bool {
+	return s.logger.Enabled(InfoLevel + level)
+}
+
+func (s *contextSink) Info(level int, msg string, keysAndValues ...interface{}) {
+	s.logger.Log(InfoLevel+level, msg, keysAndValues...)
+}
+
+func (s *contextSink) Error(err error, msg string, keysAndValues ...interface{}) {
+	s.logger.LogError(err, msg, keysAndValues...)
+}
+
+func (s *contextSink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	return s.with(s.actx.WithValues(keysAndValues...), s.names, s.depth)
+}
+
+func (s *contextSink) WithName(name string) logr.LogSink {
+	return s.with(s.actx, sliceAppend(s.names, name), s.depth)
+}
+
+func (s *contextSink) WithCallDepth(depth int) logr.LogSink {
+	return s.with(s.actx, s.names, s.depth+depth)
+}
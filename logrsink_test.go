@@ -0,0 +1,77 @@
+/*
+ * Copyright 2023 Mandelsoft. All rights reserved.
+ *  This file is licensed under the Apache Software License, v. 2 except as noted
+ *  otherwise in the LICENSE file
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+package logging_test
+
+import (
+	"bytes"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/tonglil/buflogr"
+
+	"github.com/mandelsoft/logging"
+)
+
+var _ = Describe("context sink", func() {
+	var buf bytes.Buffer
+	var ctx logging.Context
+
+	BeforeEach(func() {
+		buf.Reset()
+		ctx = logging.New(buflogr.NewWithBuffer(&buf))
+	})
+
+	It("maps V levels relative to info level", func() {
+		l := logging.NewLogr(ctx, nil)
+		l.Info("info")
+		l.V(1).Info("debug")
+		l.Error(fmt.Errorf("failed"), "error")
+		Expect(l.V(1).Enabled()).To(BeFalse())
+		Expect(buf.String()).To(Equal("V[3] info\nERROR failed error\n"))
+	})
+
+	It("maps names to realms", func() {
+		ctx.AddRule(logging.NewConditionRule(logging.DebugLevel, logging.NewRealmPrefix("controller")))
+		ctx.AddRule(logging.NewConditionRule(logging.TraceLevel, logging.NewRealm("controller/foo")))
+
+		l := logging.NewLogr(ctx, nil).WithName("controller")
+		l.V(1).Info("debug")
+		l.V(2).Info("trace")
+		l.WithName("foo").V(2).Info("trace", "key", "value")
+		Expect(buf.String()).To(Equal("V[4] controller debug realm controller\nV[5] controller:foo trace realm controller/foo key value\n"))
+	})
+
+	It("reflects rule changes", func() {
+		l := logging.NewLogr(ctx, nil).WithName("controller").WithValues("key", "value")
+		Expect(l.V(1).Enabled()).To(BeFalse())
+		ctx.AddRule(logging.NewConditionRule(logging.DebugLevel, logging.NewRealm("controller")))
+		Expect(l.V(1).Enabled()).To(BeTrue())
+		l.V(1).Info("debug")
+		Expect(buf.String()).To(Equal("V[4] controller debug realm controller key value\n"))
+	})
+
+	It("uses custom mapping", func() {
+		ctx.AddRule(logging.NewConditionRule(logging.DebugLevel, logging.NewRealmPrefix("lib")))
+		l := logging.NewLogr(ctx, logging.RealmMapping("lib"))
+		l.V(1).Info("debug")
+		l.WithName("sub").V(1).Info("debug")
+		Expect(buf.String()).To(Equal("V[4] debug realm lib\nV[4] sub debug realm lib/sub\n"))
+	})
+})
@@ -19,6 +19,8 @@
 package logrusfmt
 
 import (
+	"sync"
+
 	"github.com/sirupsen/logrus"
 )
 
@@ -53,12 +55,46 @@ var defaultFixedFields = []string{
 	FieldKeyFile,
 }
 
-var maxlevellength int
+var levels = struct {
+	lock      sync.RWMutex
+	names     map[logrus.Level]string
+	maxlength int
+}{names: map[logrus.Level]string{}}
 
 func init() {
 	for _, l := range AllLevels {
-		if len(l.String()) > maxlevellength {
-			maxlevellength = len(l.String())
+		if len(l.String()) > levels.maxlength {
+			levels.maxlength = len(l.String())
 		}
 	}
 }
+
+// SetLevelName sets the name used by the formatters to render a level.
+// It is intended for levels beyond TraceLevel, which are unknown to logrus.
+func SetLevelName(l logrus.Level, name string) {
+	levels.lock.Lock()
+	defer levels.lock.Unlock()
+
+	levels.names[l] = name
+	if len(name) > levels.maxlength {
+		levels.maxlength = len(name)
+	}
+}
+
+// LevelName returns the name used by the formatters to render a level.
+func LevelName(l logrus.Level) string {
+	levels.lock.RLock()
+	defer levels.lock.RUnlock()
+
+	if n, ok := levels.names[l]; ok {
+		return n
+	}
+	return l.String()
+}
+
+func maxLevelLength() int {
+	levels.lock.RLock()
+	defer levels.lock.RUnlock()
+
+	return levels.maxlength
+}
@@ -140,7 +140,7 @@ func (f *JSONFormatter) Format(entry *Entry) ([]byte, error) {
 				fixedKeys = append(fixedKeys, effName)
 			}
 		case FieldKeyLevel:
-			data[effName] = entry.Level.String()
+			data[effName] = LevelName(entry.Level)
 			fixedKeys = append(fixedKeys, effName)
 		case FieldKeyMsg:
 			if entry.Message != "" {
@@ -93,7 +93,7 @@ func LevelValue(w io.Writer, key string, value interface{}, needsQuoting func(st
 	if value == utils.Ignore {
 		return
 	}
-	f := fmt.Sprintf(fmt.Sprintf("%%-%ds", maxlevellength), value.(string))
+	f := fmt.Sprintf(fmt.Sprintf("%%-%ds", maxLevelLength()), value.(string))
 	PlainValue(w, key, f, func(string) bool { return false })
 }
 
@@ -301,7 +301,7 @@ func (f *TextFormatter) Format(entry *Entry) ([]byte, error) {
 			}
 		case FieldKeyLevel:
 			fixedKeys = append(fixedKeys, effname)
-			data[effname] = entry.Level.String()
+			data[effname] = LevelName(entry.Level)
 		case FieldKeyMsg:
 			if entry.Message != "" {
 				fixedKeys = append(fixedKeys, effname)
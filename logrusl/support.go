@@ -21,6 +21,7 @@ package logrusl
 import (
 	"io"
 	"os"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/mandelsoft/logging"
@@ -35,6 +36,10 @@ import (
 type Settings struct {
 	Writer    io.Writer
 	Formatter logrus.Formatter
+	// Deduplication is the time window used to suppress identical
+	// consecutive log records (see logging.NewDeduplicatingSink).
+	// If zero, no deduplication is done.
+	Deduplication time.Duration
 }
 
 func (s Settings) WithWriter(w io.Writer) Settings {
@@ -47,6 +52,11 @@ func (s Settings) WithFormatter(f logrus.Formatter) Settings {
 	return s
 }
 
+func (s Settings) WithDeduplication(window time.Duration) Settings {
+	s.Deduplication = window
+	return s
+}
+
 func (s Settings) Human(padded ...bool) Settings {
 	s.Formatter = adapter.NewTextFmtFormatter(padded...)
 	return s
@@ -58,7 +68,11 @@ func (s Settings) JSON() Settings {
 }
 
 func (s Settings) NewLogr() logr.Logger {
-	return logrusr.New(s.NewLogrus())
+	l := logrusr.New(s.NewLogrus())
+	if s.Deduplication > 0 {
+		l = logr.New(logging.NewDeduplicatingSink(l.GetSink(), s.Deduplication))
+	}
+	return l
 }
 
 func (s Settings) NewLogrus() *logrus.Logger {
@@ -75,7 +89,7 @@ func (s Settings) NewLogrus() *logrus.Logger {
 }
 
 func (s Settings) New() logging.Context {
-	return logging.New(logrusr.New(s.NewLogrus()))
+	return logging.New(s.NewLogr())
 }
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -96,6 +110,10 @@ func WithFormatter(f logrus.Formatter) Settings {
 	return Settings{}.WithFormatter(f)
 }
 
+func WithDeduplication(window time.Duration) Settings {
+	return Settings{}.WithDeduplication(window)
+}
+
 func Human(padded ...bool) Settings {
 	return Settings{}.Human(padded...)
 }
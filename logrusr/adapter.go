@@ -101,7 +101,13 @@ func (l *logrusr) Enabled(level int) bool {
 	// logrus.InfoLevel has value 4 so if the level on the logger is set to 0 we
 	// should only be seen as enabled if the logrus logger has a severity of
 	// info or higher.
-	return l.logger.Logger.IsLevelEnabled(logrus.Level(level + minlevel - 1))
+	return l.logger.Logger.IsLevelEnabled(LogrusLevel(level))
+}
+
+// LogrusLevel maps a logr level to the logrus level used to issue
+// log records.
+func LogrusLevel(level int) logrus.Level {
+	return logrus.Level(level + minlevel - 1)
 }
 
 // Info logs info messages if the logger is enabled, that is if the level on the
@@ -114,7 +120,7 @@ func (l *logrusr) Info(level int, msg string, keysAndValues ...interface{}) {
 
 	log.
 		WithFields(listToLogrusFields(l.defaultFormatter, keysAndValues...)).
-		Log(logrus.Level(level+minlevel-1), msg)
+		Log(LogrusLevel(level), msg)
 }
 
 // Error logs error messages. Since the log will be written with `Error` level
@@ -206,10 +212,10 @@ func (l *logrusr) copyLogger() *logrusr {
 }
 
 // WithCallDepth implements the optional WithCallDepth to offset the call stack
-// when reporting caller.
+// when reporting caller. The depth is added to the actual call depth.
 func (l *logrusr) WithCallDepth(depth int) logr.LogSink {
 	newLogger := l.copyLogger()
-	newLogger.depth = depth
+	newLogger.depth += depth
 
 	return newLogger
 }
@@ -223,8 +229,9 @@ func (l *logrusr) caller() string {
 
 	// +1 for this frame.
 	// +1 for frame calling here (Info/Error)
-	// +1 for logr frame
-	_, file, line, ok := runtime.Caller(l.depth + 3)
+	// +depth for the frames between the sink and the end-user
+	// (the logr frame and additional wrappers).
+	_, file, line, ok := runtime.Caller(l.depth + 2)
 	if !ok {
 		return ""
 	}
@@ -29,6 +29,7 @@ import (
 
 	"github.com/mandelsoft/logging"
 	"github.com/mandelsoft/logging/logrusr"
+	"github.com/mandelsoft/logging/utils"
 )
 
 var _ = Describe("mapping test", func() {
@@ -66,4 +67,14 @@ var _ = Describe("mapping test", func() {
 		Expect(buf.String()).To(Equal("{\"error\":\"errmsg\",\"level\":\"error\",\"msg\":\"test\"}\n"))
 	})
 
+	It("resolves lazy values", func() {
+		buf := &bytes.Buffer{}
+		log := logrus.New()
+		log.SetLevel(9)
+		log.SetFormatter(&logrus.JSONFormatter{DisableTimestamp: true})
+		log.SetOutput(buf)
+		logrusr.New(log).GetSink().Info(logging.InfoLevel, "test", "key", utils.Lazy(func() interface{} { return "value" }))
+		Expect(buf.String()).To(Equal("{\"key\":\"value\",\"level\":\"info\",\"msg\":\"test\"}\n"))
+	})
+
 })
@@ -42,8 +42,10 @@ func DetermineLogWriter(s logr.LogSink) io.Writer {
 		if ok {
 			return w
 		}
-		if lw, ok := s.(LogWriter); ok && w != nil {
-			return lw.LogWriter()
+		if lw, ok := s.(LogWriter); ok {
+			if w := lw.LogWriter(); w != nil {
+				return w
+			}
 		}
 		u, ok := s.(interface{ Unwrap() logr.LogSink })
 		if ok {
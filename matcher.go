@@ -0,0 +1,309 @@
+/*
+ * Copyright 2023 Mandelsoft. All rights reserved.
+ *  This file is licensed under the Apache Software License, v. 2 except as noted
+ *  otherwise in the LICENSE file
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package logging
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ValueMatcher can be used as value of an Attribute condition
+// to match a set of attribute values instead of a dedicated one.
+// It may be used as value for a KeyValueMatch record condition, also.
+type ValueMatcher interface {
+	MatchValue(v interface{}) bool
+}
+
+// EqualsMatcher matches values equal to a given value.
+// Numbers are compared by their numeric value regardless of their
+// type, strings are compared with the string representation of
+// other values, for example "5" matches the int value 5.
+type EqualsMatcher struct {
+	value interface{}
+}
+
+var _ ValueMatcher = (*EqualsMatcher)(nil)
+
+func NewEqualsMatcher(value interface{}) ValueMatcher {
+	return &EqualsMatcher{value: value}
+}
+
+func (m *EqualsMatcher) Value() interface{} {
+	return m.value
+}
+
+func (m *EqualsMatcher) MatchValue(v interface{}) bool {
+	return coercedEqual(m.value, v)
+}
+
+// RegexMatcher matches values whose string representation
+// matches a regular expression.
+type RegexMatcher struct {
+	expr *regexp.Regexp
+}
+
+var _ ValueMatcher = (*RegexMatcher)(nil)
+
+func NewRegexMatcher(expr string) (ValueMatcher, error) {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	return &RegexMatcher{expr: re}, nil
+}
+
+func (m *RegexMatcher) Expression() string {
+	return m.expr.String()
+}
+
+func (m *RegexMatcher) MatchValue(v interface{}) bool {
+	return m.expr.MatchString(stringValue(v))
+}
+
+// GlobMatcher matches values whose string representation
+// matches a glob pattern (see MessageGlob).
+type GlobMatcher struct {
+	pattern string
+	expr    *regexp.Regexp
+}
+
+var _ ValueMatcher = (*GlobMatcher)(nil)
+
+func NewGlobMatcher(pattern string) ValueMatcher {
+	return &GlobMatcher{pattern: pattern, expr: globExpr(pattern)}
+}
+
+func (m *GlobMatcher) Pattern() string {
+	return m.pattern
+}
+
+func (m *GlobMatcher) MatchValue(v interface{}) bool {
+	return m.expr.MatchString(stringValue(v))
+}
+
+// InMatcher matches values equal to one of a set of values.
+// Values are compared like by the EqualsMatcher.
+type InMatcher struct {
+	values []interface{}
+}
+
+var _ ValueMatcher = (*InMatcher)(nil)
+
+func NewInMatcher(values ...interface{}) ValueMatcher {
+	return &InMatcher{values: values}
+}
+
+func (m *InMatcher) Values() []interface{} {
+	return sliceCopy(m.values)
+}
+
+func (m *InMatcher) MatchValue(v interface{}) bool {
+	for _, e := range m.values {
+		if coercedEqual(e, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// RangeMatcher matches numeric values in a closed interval.
+// Strings are matched, if they represent a number. Open intervals can be
+// described with math.Inf. Other values never match.
+type RangeMatcher struct {
+	min float64
+	max float64
+}
+
+var _ ValueMatcher = (*RangeMatcher)(nil)
+
+func NewRangeMatcher(min, max float64) ValueMatcher {
+	return &RangeMatcher{min: min, max: max}
+}
+
+func (m *RangeMatcher) Min() float64 {
+	return m.min
+}
+
+func (m *RangeMatcher) Max() float64 {
+	return m.max
+}
+
+func (m *RangeMatcher) MatchValue(v interface{}) bool {
+	f, ok := numericValue(v)
+	if !ok {
+		f, ok = parseNumber(v)
+	}
+	return ok && f >= m.min && f <= m.max
+}
+
+// PresentMatcher matches any value. Used for an Attribute condition,
+// it matches message contexts with an attribute of the given name.
+type PresentMatcher struct{}
+
+var _ ValueMatcher = PresentMatcher{}
+
+func NewPresentMatcher() ValueMatcher {
+	return PresentMatcher{}
+}
+
+func (m PresentMatcher) MatchValue(v interface{}) bool {
+	return true
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// globExpr provides a regular expression matching a complete string
+// for a glob pattern with the wildcards * and ?.
+func globExpr(pattern string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^")
+	for _, c := range pattern {
+		switch c {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+func stringValue(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprint(v)
+}
+
+// numericValue provides the value of numeric types as float64.
+func numericValue(v interface{}) (float64, bool) {
+	if v == nil {
+		return 0, false
+	}
+	r := reflect.ValueOf(v)
+	switch r.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(r.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(r.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return r.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+func parseNumber(v interface{}) (float64, bool) {
+	s, ok := v.(string)
+	if !ok {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	return f, err == nil && !math.IsNaN(f)
+}
+
+// integerValue provides the value of integer types by its sign
+// and absolute value, which can be compared exactly.
+func integerValue(v interface{}) (bool, uint64, bool) {
+	if v == nil {
+		return false, 0, false
+	}
+	r := reflect.ValueOf(v)
+	switch r.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i := r.Int()
+		if i < 0 {
+			return true, uint64(-(i + 1)) + 1, true
+		}
+		return false, uint64(i), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return false, r.Uint(), true
+	default:
+		return false, 0, false
+	}
+}
+
+// parseInteger provides the integer value of a string
+// like integerValue.
+func parseInteger(s string) (bool, uint64, bool) {
+	s = strings.TrimSpace(s)
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return integerValue(i)
+	}
+	if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return false, u, true
+	}
+	return false, 0, false
+}
+
+// numericEqual compares two values by their numeric value,
+// if both are numbers. Integers are compared exactly, only if
+// a float is involved the values are compared as float64.
+func numericEqual(a, b interface{}) bool {
+	if na, ia, ok := integerValue(a); ok {
+		if nb, ib, ok := integerValue(b); ok {
+			return na == nb && ia == ib
+		}
+	}
+	fa, ok := numericValue(a)
+	if !ok {
+		return false
+	}
+	fb, ok := numericValue(b)
+	return ok && fa == fb
+}
+
+// coercedEqual compares two values by their numeric value, if both
+// are numbers or one is a number and the other one a string representing
+// a number. If only one value is a string it is compared to the
+// string representation of the other one.
+func coercedEqual(a, b interface{}) bool {
+	if reflect.DeepEqual(a, b) || numericEqual(a, b) {
+		return true
+	}
+	sa, oka := a.(string)
+	sb, okb := b.(string)
+	if oka == okb {
+		return false
+	}
+	if oka {
+		return stringEqual(sa, b)
+	}
+	return stringEqual(sb, a)
+}
+
+func stringEqual(s string, v interface{}) bool {
+	if n, i, ok := integerValue(v); ok {
+		if np, ip, ok := parseInteger(s); ok {
+			return n == np && i == ip
+		}
+	}
+	if f, ok := numericValue(v); ok {
+		p, ok := parseNumber(s)
+		return ok && p == f
+	}
+	return s == fmt.Sprint(v)
+}
@@ -0,0 +1,164 @@
+/*
+ * Copyright 2023 Mandelsoft. All rights reserved.
+ *  This file is licensed under the Apache Software License, v. 2 except as noted
+ *  otherwise in the LICENSE file
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package logging_test
+
+import (
+	"bytes"
+	"math"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/tonglil/buflogr"
+
+	"github.com/mandelsoft/logging"
+)
+
+var _ = Describe("value matchers", func() {
+	Context("matching", func() {
+		It("matches equal values with coercion", func() {
+			m := logging.NewEqualsMatcher(5)
+			Expect(m.MatchValue(5)).To(BeTrue())
+			Expect(m.MatchValue(5.0)).To(BeTrue())
+			Expect(m.MatchValue(int64(5))).To(BeTrue())
+			Expect(m.MatchValue("5")).To(BeTrue())
+			Expect(m.MatchValue(" 5.0")).To(BeTrue())
+			Expect(m.MatchValue(6)).To(BeFalse())
+			Expect(m.MatchValue("five")).To(BeFalse())
+
+			m = logging.NewEqualsMatcher("true")
+			Expect(m.MatchValue(true)).To(BeTrue())
+			Expect(m.MatchValue("true")).To(BeTrue())
+			Expect(m.MatchValue("TRUE")).To(BeFalse())
+		})
+
+		It("compares large integers exactly", func() {
+			m := logging.NewEqualsMatcher(int64(9007199254740993))
+			Expect(m.MatchValue(int64(9007199254740993))).To(BeTrue())
+			Expect(m.MatchValue(uint64(9007199254740993))).To(BeTrue())
+			Expect(m.MatchValue("9007199254740993")).To(BeTrue())
+			Expect(m.MatchValue(int64(9007199254740992))).To(BeFalse())
+			Expect(m.MatchValue("9007199254740992")).To(BeFalse())
+
+			Expect(logging.NewEqualsMatcher(int64(-1)).MatchValue(uint64(math.MaxUint64))).To(BeFalse())
+			Expect(logging.NewEqualsMatcher(int64(math.MinInt64)).MatchValue(int64(math.MinInt64))).To(BeTrue())
+			Expect(logging.NewEqualsMatcher(uint64(math.MaxUint64)).MatchValue("18446744073709551615")).To(BeTrue())
+		})
+
+		It("matches regular expressions", func() {
+			m, err := logging.NewRegexMatcher("^acme-[0-9]+$")
+			Expect(err).To(Succeed())
+			Expect(m.MatchValue("acme-42")).To(BeTrue())
+			Expect(m.MatchValue("acme-x")).To(BeFalse())
+
+			_, err = logging.NewRegexMatcher("(")
+			Expect(err).NotTo(Succeed())
+		})
+
+		It("matches glob patterns", func() {
+			m := logging.NewGlobMatcher("acme-*")
+			Expect(m.MatchValue("acme-42")).To(BeTrue())
+			Expect(m.MatchValue("other-acme-42")).To(BeFalse())
+			Expect(logging.NewGlobMatcher("4?").MatchValue(42)).To(BeTrue())
+		})
+
+		It("matches sets", func() {
+			m := logging.NewInMatcher("a", 1)
+			Expect(m.MatchValue("a")).To(BeTrue())
+			Expect(m.MatchValue(1.0)).To(BeTrue())
+			Expect(m.MatchValue("b")).To(BeFalse())
+		})
+
+		It("matches ranges", func() {
+			m := logging.NewRangeMatcher(1, 10)
+			Expect(m.MatchValue(1)).To(BeTrue())
+			Expect(m.MatchValue(uint8(10))).To(BeTrue())
+			Expect(m.MatchValue(5.5)).To(BeTrue())
+			Expect(m.MatchValue("7")).To(BeTrue())
+			Expect(m.MatchValue(11)).To(BeFalse())
+			Expect(m.MatchValue("x")).To(BeFalse())
+			Expect(m.MatchValue(nil)).To(BeFalse())
+
+			Expect(logging.NewRangeMatcher(math.Inf(-1), 0).MatchValue(-1000)).To(BeTrue())
+		})
+
+		It("matches any value", func() {
+			Expect(logging.NewPresentMatcher().MatchValue(nil)).To(BeTrue())
+		})
+	})
+
+	Context("attributes", func() {
+		var buf bytes.Buffer
+		var ctx logging.Context
+
+		BeforeEach(func() {
+			buf.Reset()
+			ctx = logging.New(buflogr.NewWithBuffer(&buf))
+		})
+
+		It("matches numbers of different types", func() {
+			ctx.AddRule(logging.NewConditionRule(logging.DebugLevel, logging.NewAttribute("count", 5.0)))
+
+			ctx.Logger(logging.NewAttribute("count", 5)).Debug("debug")
+			ctx.Logger(logging.NewAttribute("count", "5")).Debug("debug")
+			Expect(buf.String()).To(Equal("V[4] debug count 5\n"))
+		})
+
+		It("matches large integers exactly", func() {
+			ctx.AddRule(logging.NewConditionRule(logging.DebugLevel, logging.NewAttribute("id", int64(9007199254740993))))
+
+			ctx.Logger(logging.NewAttribute("id", int64(9007199254740992))).Debug("debug")
+			ctx.Logger(logging.NewAttribute("id", uint64(9007199254740993))).Debug("debug")
+			Expect(buf.String()).To(Equal("V[4] debug id 9007199254740993\n"))
+		})
+
+		It("uses value matchers", func() {
+			ctx.AddRule(logging.NewConditionRule(logging.TraceLevel, logging.NewAttribute("tenant", logging.NewGlobMatcher("acme-*"))))
+
+			ctx.Logger(logging.NewAttribute("tenant", "acme-1")).Trace("trace")
+			ctx.Logger(logging.NewAttribute("tenant", "other")).Trace("trace")
+			Expect(buf.String()).To(Equal("V[5] trace tenant acme-1\n"))
+		})
+
+		It("matches present attributes", func() {
+			ctx.AddRule(logging.NewConditionRule(logging.DebugLevel, logging.NewAttribute("tenant", logging.NewPresentMatcher())))
+
+			ctx.Logger().Debug("debug")
+			ctx.Logger(logging.NewAttribute("tenant", "any")).Debug("debug")
+			Expect(buf.String()).To(Equal("V[4] debug tenant any\n"))
+		})
+
+		It("explains value matchers", func() {
+			ctx.AddRule(logging.NewConditionRule(logging.DebugLevel, logging.NewAttribute("tenant", logging.NewInMatcher("a", "b"))))
+
+			Expect(ctx.Explain(logging.NewAttribute("tenant", "b")).String()).To(MatchRegexp(`matched rule #1 Debug\[attribute tenant in \[a b\]\]`))
+		})
+	})
+
+	It("matches record values", func() {
+		var buf bytes.Buffer
+
+		ctx := logging.New(buflogr.NewWithBuffer(&buf))
+		ctx.AddFilter(logging.NewDropFilter(logging.NewKeyValueMatch("status", logging.NewRangeMatcher(200, 299))))
+
+		ctx.Logger().Info("request", "status", 204)
+		ctx.Logger().Info("request", "status", 404)
+		Expect(buf.String()).To(Equal("V[3] request status 404\n"))
+	})
+})
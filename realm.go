@@ -19,6 +19,9 @@
 package logging
 
 import (
+	"fmt"
+	"path"
+	"regexp"
 	"runtime"
 	"strings"
 )
@@ -70,14 +73,21 @@ func (r realmprefix) Match(messageContext ...MessageContext) bool {
 ////////////////////////////////////////////////////////////////////////////////
 
 func matchRealm(r string, prefix bool, messageContext ...MessageContext) bool {
-	// match only last (most significant) realm in complete aggregated
-	// message context
+	if name, ok := lastRealm(messageContext); ok {
+		return checkRealm(r, name, prefix)
+	}
+	return false
+}
+
+// lastRealm provides the last (most significant) realm in a complete
+// aggregated message context. Realm conditions match only this realm.
+func lastRealm(messageContext []MessageContext) (string, bool) {
 	for i := len(messageContext) - 1; i >= 0; i-- {
 		if e, ok := messageContext[i].(Realm); ok {
-			return checkRealm(r, e.Name(), prefix)
+			return e.Name(), true
 		}
 	}
-	return false
+	return "", false
 }
 
 func checkRealm(r, name string, prefix bool) bool {
@@ -89,6 +99,100 @@ func checkRealm(r, name string, prefix bool) bool {
 
 ////////////////////////////////////////////////////////////////////////////////
 
+// RealmPattern is used as logging condition to match the
+// realm of a message context against a glob pattern.
+// The pattern is evaluated for the /-separated segments of
+// a realm name. A segment ** matches any number of segments
+// (including none), all other segments are matched according
+// to path.Match, for example * matches a single segment.
+// Like for Realm, only the last realm in a message context is
+// matched.
+type RealmPattern struct {
+	pattern  string
+	segments []string
+}
+
+var _ Condition = (*RealmPattern)(nil)
+
+// NewRealmPattern provides a new RealmPattern condition.
+// It fails for malformed patterns.
+func NewRealmPattern(pattern string) (*RealmPattern, error) {
+	segments := strings.Split(pattern, "/")
+	for _, seg := range segments {
+		if _, err := path.Match(seg, ""); err != nil {
+			return nil, fmt.Errorf("invalid realm pattern %q: %w", pattern, err)
+		}
+	}
+	return &RealmPattern{pattern: pattern, segments: segments}, nil
+}
+
+func (r *RealmPattern) Pattern() string {
+	return r.pattern
+}
+
+func (r *RealmPattern) Match(messageContext ...MessageContext) bool {
+	if name, ok := lastRealm(messageContext); ok {
+		return matchSegments(r.segments, strings.Split(name, "/"))
+	}
+	return false
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// RealmRegex is used as logging condition to match the
+// realm of a message context against a regular expression.
+// The expression must match the complete realm name.
+// Like for Realm, only the last realm in a message context is
+// matched.
+type RealmRegex struct {
+	expr   string
+	regexp *regexp.Regexp
+}
+
+var _ Condition = (*RealmRegex)(nil)
+
+// NewRealmRegex provides a new RealmRegex condition.
+// It fails for invalid regular expressions.
+func NewRealmRegex(expr string) (*RealmRegex, error) {
+	re, err := regexp.Compile("^(?:" + expr + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid realm expression %q: %w", expr, err)
+	}
+	return &RealmRegex{expr: expr, regexp: re}, nil
+}
+
+func (r *RealmRegex) Expression() string {
+	return r.expr
+}
+
+func (r *RealmRegex) Match(messageContext ...MessageContext) bool {
+	if name, ok := lastRealm(messageContext); ok {
+		return r.regexp.MatchString(name)
+	}
+	return false
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
 func Package() Realm {
 	pc, _, _, ok := runtime.Caller(1)
 	if !ok {
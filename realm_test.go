@@ -0,0 +1,105 @@
+/*
+ * Copyright 2023 Mandelsoft. All rights reserved.
+ *  This file is licensed under the Apache Software License, v. 2 except as noted
+ *  otherwise in the LICENSE file
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package logging_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/tonglil/buflogr"
+
+	"github.com/mandelsoft/logging"
+)
+
+var _ = Describe("realm patterns", func() {
+	pattern := func(p string) *logging.RealmPattern {
+		r, err := logging.NewRealmPattern(p)
+		ExpectWithOffset(1, err).To(Succeed())
+		return r
+	}
+
+	realm := func(name string) logging.MessageContext {
+		return logging.NewRealm(name)
+	}
+
+	Context("glob", func() {
+		It("matches single segments", func() {
+			p := pattern("*/webhook")
+			Expect(p.Match(realm("mod/webhook"))).To(BeTrue())
+			Expect(p.Match(realm("webhook"))).To(BeFalse())
+			Expect(p.Match(realm("a/mod/webhook"))).To(BeFalse())
+			Expect(p.Match(realm("mod/webhooks"))).To(BeFalse())
+			Expect(pattern("mod/web*").Match(realm("mod/webhook"))).To(BeTrue())
+		})
+
+		It("matches multiple segments", func() {
+			p := pattern("github.com/our-org/*/controllers/**")
+			Expect(p.Match(realm("github.com/our-org/mod/controllers"))).To(BeTrue())
+			Expect(p.Match(realm("github.com/our-org/mod/controllers/a/b"))).To(BeTrue())
+			Expect(p.Match(realm("github.com/our-org/mod/sub/controllers/a"))).To(BeFalse())
+
+			p = pattern("**/webhook")
+			Expect(p.Match(realm("webhook"))).To(BeTrue())
+			Expect(p.Match(realm("a/b/webhook"))).To(BeTrue())
+			Expect(p.Match(realm("a/b/webhook/c"))).To(BeFalse())
+		})
+
+		It("matches the last realm, only", func() {
+			p := pattern("*/webhook")
+			Expect(p.Match(realm("mod/webhook"), realm("other"))).To(BeFalse())
+			Expect(p.Match(realm("other"), realm("mod/webhook"))).To(BeTrue())
+			Expect(p.Match(logging.NewTag("mod/webhook"))).To(BeFalse())
+		})
+
+		It("rejects invalid patterns", func() {
+			_, err := logging.NewRealmPattern("mod/[a")
+			Expect(err).To(MatchError(`invalid realm pattern "mod/[a": syntax error in pattern`))
+		})
+	})
+
+	Context("regex", func() {
+		It("matches complete realm names", func() {
+			r, err := logging.NewRealmRegex(`.*/(webhook|admission)`)
+			Expect(err).To(Succeed())
+			Expect(r.Match(realm("mod/webhook"))).To(BeTrue())
+			Expect(r.Match(realm("mod/admission"))).To(BeTrue())
+			Expect(r.Match(realm("mod/webhook/sub"))).To(BeFalse())
+			Expect(r.Match(realm("mod/webhook"), realm("other"))).To(BeFalse())
+		})
+
+		It("rejects invalid expressions", func() {
+			_, err := logging.NewRealmRegex("(")
+			Expect(err).To(MatchError(ContainSubstring(`invalid realm expression "("`)))
+		})
+	})
+
+	It("is used by rules", func() {
+		var buf bytes.Buffer
+
+		ctx := logging.New(buflogr.NewWithBuffer(&buf))
+		ctx.AddRule(logging.NewConditionRule(logging.DebugLevel, pattern("**/webhook")))
+
+		ctx.Logger(realm("mod/webhook")).Debug("debug")
+		ctx.Logger(realm("mod/other")).Debug("debug")
+		Expect(buf.String()).To(Equal("V[4] debug realm mod/webhook\n"))
+		Expect(ctx.Explain(realm("mod/webhook")).String()).To(ContainSubstring("Debug[realmpattern **/webhook]"))
+	})
+})
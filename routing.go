@@ -0,0 +1,131 @@
+/*
+ * Copyright 2023 Mandelsoft. All rights reserved.
+ *  This file is licensed under the Apache Software License, v. 2 except as noted
+ *  otherwise in the LICENSE file
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package logging
+
+import (
+	"reflect"
+
+	"github.com/go-logr/logr"
+)
+
+// RoutingRule is a rule routing log messages of a message context
+// matching all given conditions to a dedicated logr.LogSink
+// instead of the base sink of the logging context.
+// The sink is either given directly or by the name of a sink
+// registered at the logging context owning the rule
+// (see Context.SetNamedSink).
+type RoutingRule struct {
+	conditions []Condition
+	level      int
+	sink       logr.LogSink
+	name       string
+}
+
+var _ ContextRule = (*RoutingRule)(nil)
+var _ UpdatableRule = (*RoutingRule)(nil)
+
+// NewRoutingRule provides a rule routing all log messages for a message
+// context matching the given conditions to the given sink.
+// The sink is used as it is, the logging levels are passed as they are
+// (plain mode).
+// If the level is negative, the default level of the logging context owning
+// the rule is used.
+func NewRoutingRule(sink logr.LogSink, level int, cond ...Condition) Rule {
+	return &RoutingRule{
+		conditions: cond,
+		level:      level,
+		sink:       sink,
+	}
+}
+
+// NewNamedRoutingRule provides a rule routing all log messages for a
+// message context matching the given conditions to the sink registered
+// with the given name at the logging context owning the rule.
+// If there is no such sink, the base sink is used.
+// If the level is negative, the default level of the logging context owning
+// the rule is used.
+func NewNamedRoutingRule(name string, level int, cond ...Condition) Rule {
+	return &RoutingRule{
+		conditions: cond,
+		level:      level,
+		name:       name,
+	}
+}
+
+// MatchRule supersedes named routing rules for the same
+// sink name and conditions.
+func (r *RoutingRule) MatchRule(o Rule) bool {
+	if or, ok := o.(*RoutingRule); ok {
+		return r.name != "" && r.name == or.name && reflect.DeepEqual(r.conditions, or.conditions)
+	}
+	return false
+}
+
+func (r *RoutingRule) Match(sink SinkFunc, messageContext ...MessageContext) Logger {
+	return r.MatchWithContext(LoggingContext(nil), sink, messageContext...)
+}
+
+func (r *RoutingRule) MatchWithContext(ctx Context, sink SinkFunc, messageContext ...MessageContext) Logger {
+	for _, c := range r.conditions {
+		if !c.Match(messageContext...) {
+			return nil
+		}
+	}
+
+	level := AsLevelFunc(r.level)
+	if r.level < 0 {
+		level = ctx.GetDefaultLevel
+	}
+	if r.sink != nil {
+		return NewLogger(DynSink(level, 0, AsSinkFunc(r.sink)))
+	}
+	return NewLogger(DynSink(level, 0, func() logr.LogSink {
+		if s := ctx.GetNamedSink(r.name); s != nil {
+			return s
+		}
+		return sink()
+	}))
+}
+
+func (r *RoutingRule) Level() int {
+	return r.level
+}
+
+func (r *RoutingRule) Conditions() []Condition {
+	return sliceCopy(r.conditions)
+}
+
+// SinkName returns the name of the target sink for a named routing rule.
+func (r *RoutingRule) SinkName() string {
+	return r.name
+}
+
+// Sink returns the target sink of a routing rule, if it has
+// been given directly.
+func (r *RoutingRule) Sink() logr.LogSink {
+	return r.sink
+}
+
+// matchRule evaluates a rule owned by a context.
+func matchRule(ctx Context, rule Rule, base SinkFunc, messageContext ...MessageContext) Logger {
+	if r, ok := rule.(ContextRule); ok {
+		return r.MatchWithContext(ctx, base, messageContext...)
+	}
+	return rule.Match(base, messageContext...)
+}
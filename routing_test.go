@@ -0,0 +1,119 @@
+/*
+ * Copyright 2023 Mandelsoft. All rights reserved.
+ *  This file is licensed under the Apache Software License, v. 2 except as noted
+ *  otherwise in the LICENSE file
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package logging_test
+
+import (
+	"bytes"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/tonglil/buflogr"
+
+	"github.com/mandelsoft/logging"
+)
+
+var _ = Describe("routing rules", func() {
+	var buf bytes.Buffer
+	var audit bytes.Buffer
+	var ctx logging.Context
+
+	realm := logging.NewRealm("compliance")
+
+	BeforeEach(func() {
+		buf.Reset()
+		audit.Reset()
+		ctx = logging.New(buflogr.NewWithBuffer(&buf))
+	})
+
+	It("routes to sink", func() {
+		ctx.AddRule(logging.NewRoutingRule(buflogr.NewWithBuffer(&audit).GetSink(), logging.DebugLevel, realm))
+
+		ctx.Logger().Info("info")
+		ctx.Logger(realm).Debug("debug")
+
+		Expect("\n" + buf.String()).To(Equal(`
+V[3] info
+`))
+		Expect("\n" + audit.String()).To(Equal(`
+V[4] debug realm compliance
+`))
+	})
+
+	It("routes to named sink with default level", func() {
+		ctx.AddRule(logging.NewNamedRoutingRule("audit", -1, realm))
+		ctx.Logger(realm).Info("before")
+		ctx.SetNamedSink("audit", buflogr.NewWithBuffer(&audit))
+
+		ctx.Logger(realm).Info("info")
+		ctx.Logger(realm).Debug("debug")
+		ctx.SetDefaultLevel(logging.DebugLevel)
+		ctx.Logger(realm).Debug("debug")
+
+		Expect("\n" + buf.String()).To(Equal(`
+V[3] before realm compliance
+`))
+		Expect("\n" + audit.String()).To(Equal(`
+V[3] info realm compliance
+V[4] debug realm compliance
+`))
+	})
+
+	It("resolves named sink of base context", func() {
+		ctx.SetNamedSink("audit", buflogr.NewWithBuffer(&audit))
+		nested := logging.NewWithBase(ctx)
+		nested.AddRule(logging.NewNamedRoutingRule("audit", logging.InfoLevel, realm))
+
+		nested.Logger(realm).Info("info")
+		Expect("\n" + audit.String()).To(Equal(`
+V[3] info realm compliance
+`))
+		Expect(buf.String()).To(Equal(""))
+	})
+
+	It("routes to named sink for temporary rules", func() {
+		ctx.SetNamedSink("audit", buflogr.NewWithBuffer(&audit))
+		ctx.AddRule(logging.NewTemporaryRule(logging.NewNamedRoutingRule("audit", logging.InfoLevel, realm), time.Hour))
+		now := time.Now()
+		tod := now.Sub(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()))
+		ctx.AddRule(logging.NewTimeWindowRule(logging.NewNamedRoutingRule("audit", logging.InfoLevel, logging.NewTag("tag")), tod+23*time.Hour, tod+time.Hour))
+
+		ctx.Logger(realm).Info("info")
+		ctx.Logger(logging.NewTag("tag")).Info("tagged")
+		Expect("\n" + audit.String()).To(Equal(`
+V[3] info realm compliance
+V[3] tagged
+`))
+		Expect(buf.String()).To(Equal(""))
+	})
+
+	It("flushes temporary rules", func() {
+		ctx.AddRule(logging.NewTemporaryRule(logging.NewDeduplicationRule(logging.InfoLevel, time.Hour, realm), time.Hour))
+
+		for i := 0; i < 3; i++ {
+			ctx.Logger(realm).Info("loop")
+		}
+		logging.Flush(ctx)
+		Expect("\n" + buf.String()).To(Equal(`
+V[3] loop realm compliance
+V[3] loop realm compliance repeated 2
+`))
+	})
+})
@@ -20,6 +20,7 @@ package logging
 
 import (
 	"reflect"
+	"time"
 )
 
 type ConditionRule struct {
@@ -61,3 +62,201 @@ func (r *ConditionRule) Level() int {
 func (r *ConditionRule) Conditions() []Condition {
 	return sliceCopy(r.conditions)
 }
+
+////////////////////////////////////////////////////////////////////////////////
+
+// ExpiringRule is a rule, which is only active until a dedicated
+// point in time. After its expiry it does not match anymore and
+// is automatically removed from the rule set of a logging context.
+type ExpiringRule struct {
+	rule   Rule
+	expiry time.Time
+}
+
+var _ TemporaryRule = (*ExpiringRule)(nil)
+var _ UpdatableRule = (*ExpiringRule)(nil)
+var _ ContextRule = (*ExpiringRule)(nil)
+var _ Flusher = (*ExpiringRule)(nil)
+
+// NewExpiringRule provides a rule, which behaves like the given rule
+// until the given expiry time.
+func NewExpiringRule(rule Rule, expiry time.Time) Rule {
+	return &ExpiringRule{
+		rule:   rule,
+		expiry: expiry,
+	}
+}
+
+// NewTemporaryRule provides a rule, which behaves like the given rule
+// for the given duration.
+func NewTemporaryRule(rule Rule, d time.Duration) Rule {
+	return NewExpiringRule(rule, time.Now().Add(d))
+}
+
+// MatchRule supersedes expiring rules for a matching
+// (updatable) rule. Rules without expiry are never superseded,
+// so that they are still present after the expiry of the new rule.
+func (r *ExpiringRule) MatchRule(o Rule) bool {
+	if or, ok := o.(*ExpiringRule); ok {
+		if upd, ok := r.rule.(UpdatableRule); ok {
+			return upd.MatchRule(or.rule)
+		}
+	}
+	return false
+}
+
+func (r *ExpiringRule) Match(sink SinkFunc, messageContext ...MessageContext) Logger {
+	return r.MatchWithContext(LoggingContext(nil), sink, messageContext...)
+}
+
+func (r *ExpiringRule) MatchWithContext(ctx Context, sink SinkFunc, messageContext ...MessageContext) Logger {
+	if r.Expired(time.Now()) {
+		return nil
+	}
+	return matchRule(ctx, r.rule, sink, messageContext...)
+}
+
+// Flush flushes the wrapped rule.
+func (r *ExpiringRule) Flush() {
+	if f, ok := r.rule.(Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (r *ExpiringRule) NextChange(now time.Time) time.Time {
+	if r.Expired(now) {
+		return time.Time{}
+	}
+	if t, ok := r.rule.(TemporaryRule); ok {
+		if n := t.NextChange(now); !n.IsZero() && n.Before(r.expiry) {
+			return n
+		}
+	}
+	return r.expiry
+}
+
+func (r *ExpiringRule) Expired(now time.Time) bool {
+	if !now.Before(r.expiry) {
+		return true
+	}
+	if t, ok := r.rule.(TemporaryRule); ok {
+		return t.Expired(now)
+	}
+	return false
+}
+
+func (r *ExpiringRule) Rule() Rule {
+	return r.rule
+}
+
+func (r *ExpiringRule) Expiry() time.Time {
+	return r.expiry
+}
+
+func (r *ExpiringRule) Conditions() []Condition {
+	if c, ok := r.rule.(interface{ Conditions() []Condition }); ok {
+		return c.Conditions()
+	}
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// TimeWindowRule is a rule, which is only active during a daily
+// time window.
+type TimeWindowRule struct {
+	rule Rule
+	from time.Duration
+	to   time.Duration
+}
+
+var _ TemporaryRule = (*TimeWindowRule)(nil)
+var _ ContextRule = (*TimeWindowRule)(nil)
+var _ Flusher = (*TimeWindowRule)(nil)
+
+// NewTimeWindowRule provides a rule, which behaves like the given rule
+// during a daily time window. The window is described by the time of day
+// (duration since midnight, local time) of its start and its end.
+// If the end is before the start, the window spans midnight.
+func NewTimeWindowRule(rule Rule, from, to time.Duration) Rule {
+	return &TimeWindowRule{
+		rule: rule,
+		from: from % day,
+		to:   to % day,
+	}
+}
+
+const day = 24 * time.Hour
+
+func (r *TimeWindowRule) Match(sink SinkFunc, messageContext ...MessageContext) Logger {
+	return r.MatchWithContext(LoggingContext(nil), sink, messageContext...)
+}
+
+func (r *TimeWindowRule) MatchWithContext(ctx Context, sink SinkFunc, messageContext ...MessageContext) Logger {
+	if !r.Active(time.Now()) {
+		return nil
+	}
+	return matchRule(ctx, r.rule, sink, messageContext...)
+}
+
+// Flush flushes the wrapped rule.
+func (r *TimeWindowRule) Flush() {
+	if f, ok := r.rule.(Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Active returns whether the given time is in the time window of the rule.
+func (r *TimeWindowRule) Active(now time.Time) bool {
+	t := now.Sub(midnight(now))
+	if r.from <= r.to {
+		return r.from <= t && t < r.to
+	}
+	return t >= r.from || t < r.to
+}
+
+func (r *TimeWindowRule) NextChange(now time.Time) time.Time {
+	if r.from == r.to {
+		return time.Time{}
+	}
+	if t, ok := r.rule.(TemporaryRule); ok && t.Expired(now) {
+		return time.Time{}
+	}
+	start := midnight(now)
+	tomorrow := start.AddDate(0, 0, 1)
+
+	var next time.Time
+	for _, b := range []time.Time{start.Add(r.from), start.Add(r.to), tomorrow.Add(r.from), tomorrow.Add(r.to)} {
+		if b.After(now) && (next.IsZero() || b.Before(next)) {
+			next = b
+		}
+	}
+	return next
+}
+
+func (r *TimeWindowRule) Expired(now time.Time) bool {
+	if t, ok := r.rule.(TemporaryRule); ok {
+		return t.Expired(now)
+	}
+	return false
+}
+
+func (r *TimeWindowRule) Rule() Rule {
+	return r.rule
+}
+
+func (r *TimeWindowRule) Window() (time.Duration, time.Duration) {
+	return r.from, r.to
+}
+
+func (r *TimeWindowRule) Conditions() []Condition {
+	if c, ok := r.rule.(interface{ Conditions() []Condition }); ok {
+		return c.Conditions()
+	}
+	return nil
+}
+
+func midnight(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,143 @@
+/*
+ * Copyright 2023 Mandelsoft. All rights reserved.
+ *  This file is licensed under the Apache Software License, v. 2 except as noted
+ *  otherwise in the LICENSE file
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package logging
+
+import (
+	"strings"
+)
+
+// ruleIndex provides an index for the rule set of a logging context.
+// Condition rules with a single realm or realm prefix condition are
+// indexed by a trie over the realm segments. All other rules are kept
+// in a list evaluated linearly. Rules are identified by their position
+// in the rule set, so the evaluation order of the rule set can be
+// preserved.
+type ruleIndex struct {
+	realms *realmNode
+	linear []int
+}
+
+// realmNode is a node in the trie of realm segments. It keeps the
+// position of the first realm and realm prefix rule for the realm
+// path of the node.
+type realmNode struct {
+	children map[string]*realmNode
+	realm    int
+	prefix   int
+}
+
+func newRealmNode() *realmNode {
+	return &realmNode{realm: -1, prefix: -1}
+}
+
+func newRuleIndex(rules []RuleEntry) *ruleIndex {
+	idx := &ruleIndex{realms: newRealmNode()}
+	for i, e := range rules {
+		name, prefix, ok := indexableRule(e.Rule)
+		if !ok {
+			idx.linear = append(idx.linear, i)
+			continue
+		}
+		n := idx.realms.node(name)
+		if prefix {
+			if n.prefix < 0 {
+				n.prefix = i
+			}
+		} else {
+			if n.realm < 0 {
+				n.realm = i
+			}
+		}
+	}
+	return idx
+}
+
+// indexableRule checks whether a rule can be indexed by the realm trie.
+// This is the case for condition rules using only a single
+// realm or realm prefix condition.
+func indexableRule(rule Rule) (string, bool, bool) {
+	r, ok := rule.(*ConditionRule)
+	if !ok || len(r.conditions) != 1 {
+		return "", false, false
+	}
+	switch c := r.conditions[0].(type) {
+	case realm:
+		return string(c), false, true
+	case realmprefix:
+		return string(c), true, true
+	}
+	return "", false, false
+}
+
+func (n *realmNode) node(name string) *realmNode {
+	for {
+		seg, rest, found := strings.Cut(name, "/")
+		next := n.children[seg]
+		if next == nil {
+			if n.children == nil {
+				n.children = map[string]*realmNode{}
+			}
+			next = newRealmNode()
+			n.children[seg] = next
+		}
+		n = next
+		if !found {
+			return n
+		}
+		name = rest
+	}
+}
+
+// lookup returns the position of the first indexed rule
+// matching the given realm name, or -1.
+func (n *realmNode) lookup(name string) int {
+	pos := -1
+	for {
+		seg, rest, found := strings.Cut(name, "/")
+		n = n.children[seg]
+		if n == nil {
+			return pos
+		}
+		pos = firstPosition(pos, n.prefix)
+		if !found {
+			return firstPosition(pos, n.realm)
+		}
+		name = rest
+	}
+}
+
+func firstPosition(a, b int) int {
+	if a < 0 || (b >= 0 && b < a) {
+		return b
+	}
+	return a
+}
+
+// first returns the position of the first indexed rule
+// matching the given message context, or -1.
+func (idx *ruleIndex) first(messageContext ...MessageContext) int {
+	if len(idx.realms.children) == 0 {
+		return -1
+	}
+	// like matchRealm only the last realm is relevant.
+	if name, ok := lastRealm(messageContext); ok {
+		return idx.realms.lookup(name)
+	}
+	return -1
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright 2023 Mandelsoft. All rights reserved.
+ *  This file is licensed under the Apache Software License, v. 2 except as noted
+ *  otherwise in the LICENSE file
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package logging_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/tonglil/buflogr"
+
+	"github.com/mandelsoft/logging"
+)
+
+var _ = Describe("realm rule index", func() {
+	var buf bytes.Buffer
+	var ctx logging.Context
+
+	tag := logging.NewTag("tag")
+
+	level := func(mctx ...logging.MessageContext) int {
+		l := ctx.Logger(mctx...)
+		for lvl := logging.TraceLevel; lvl > logging.None; lvl-- {
+			if l.Enabled(lvl) {
+				return lvl
+			}
+		}
+		return logging.None
+	}
+
+	BeforeEach(func() {
+		buf.Reset()
+		ctx = logging.New(buflogr.NewWithBuffer(&buf))
+	})
+
+	It("matches realms and realm prefixes", func() {
+		ctx.AddRule(logging.NewConditionRule(logging.DebugLevel, logging.NewRealmPrefix("a")))
+		ctx.AddRule(logging.NewConditionRule(logging.TraceLevel, logging.NewRealm("a/b")))
+		ctx.AddRule(logging.NewConditionRule(logging.ErrorLevel, logging.NewRealm("x")))
+
+		Expect(level(logging.NewRealm("a"))).To(Equal(logging.DebugLevel))
+		Expect(level(logging.NewRealm("a/b"))).To(Equal(logging.TraceLevel))
+		Expect(level(logging.NewRealm("a/b/c"))).To(Equal(logging.DebugLevel))
+		Expect(level(logging.NewRealm("ab"))).To(Equal(logging.InfoLevel))
+		Expect(level(logging.NewRealm("x"))).To(Equal(logging.ErrorLevel))
+		Expect(level(logging.NewRealm("x/y"))).To(Equal(logging.InfoLevel))
+	})
+
+	It("respects definition order", func() {
+		ctx.AddRule(logging.NewConditionRule(logging.TraceLevel, logging.NewRealm("a/b")))
+		ctx.AddRule(logging.NewConditionRule(logging.DebugLevel, logging.NewRealmPrefix("a")))
+
+		Expect(level(logging.NewRealm("a/b"))).To(Equal(logging.DebugLevel))
+	})
+
+	It("uses last realm of message context", func() {
+		ctx.AddRule(logging.NewConditionRule(logging.DebugLevel, logging.NewRealm("a")))
+
+		Expect(level(logging.NewRealm("a"), logging.NewRealm("b"))).To(Equal(logging.InfoLevel))
+		Expect(level(logging.NewRealm("b"), logging.NewRealm("a"))).To(Equal(logging.DebugLevel))
+	})
+
+	It("merges with linear rules", func() {
+		ctx.AddRule(logging.NewConditionRule(logging.WarnLevel, logging.NewRealm("a"), tag))
+		ctx.AddRule(logging.NewConditionRule(logging.DebugLevel, logging.NewRealmPrefix("a")))
+		ctx.AddRule(logging.NewConditionRule(logging.TraceLevel, tag))
+
+		Expect(level(logging.NewRealm("a"), tag)).To(Equal(logging.TraceLevel))
+		Expect(level(logging.NewRealm("a"))).To(Equal(logging.DebugLevel))
+
+		ctx.RemoveRule("#3")
+		Expect(level(logging.NewRealm("a"), tag)).To(Equal(logging.DebugLevel))
+
+		ctx.RemoveRule("#2")
+		Expect(level(logging.NewRealm("a"), tag)).To(Equal(logging.WarnLevel))
+	})
+
+	It("updates index with rule set", func() {
+		ctx.AddNamedRule("realm", logging.NewConditionRule(logging.DebugLevel, logging.NewRealm("a")))
+		Expect(level(logging.NewRealm("a"))).To(Equal(logging.DebugLevel))
+
+		ctx.ReplaceRule("realm", logging.NewConditionRule(logging.TraceLevel, logging.NewRealm("a")))
+		Expect(level(logging.NewRealm("a"))).To(Equal(logging.TraceLevel))
+
+		ctx.ResetRules()
+		Expect(level(logging.NewRealm("a"))).To(Equal(logging.InfoLevel))
+	})
+})
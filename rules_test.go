@@ -0,0 +1,183 @@
+/*
+ * Copyright 2023 Mandelsoft. All rights reserved.
+ *  This file is licensed under the Apache Software License, v. 2 except as noted
+ *  otherwise in the LICENSE file
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package logging_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/tonglil/buflogr"
+
+	"github.com/mandelsoft/logging"
+)
+
+var _ = Describe("rule management", func() {
+	var buf bytes.Buffer
+	var ctx logging.Context
+
+	realm := logging.NewRealm("realm")
+	tag := logging.NewTag("tag")
+
+	BeforeEach(func() {
+		buf.Reset()
+		ctx = logging.New(buflogr.NewWithBuffer(&buf))
+	})
+
+	It("lists rules in evaluation order", func() {
+		r1 := logging.NewConditionRule(logging.DebugLevel, realm)
+		r2 := logging.NewConditionRule(logging.TraceLevel, tag)
+		ctx.AddRule(r1, r2)
+		ctx.AddNamedRule("named", logging.NewConditionRule(logging.WarnLevel))
+
+		rules := ctx.Rules()
+		Expect(len(rules)).To(Equal(3))
+		Expect(rules[0].Id).To(Equal("named"))
+		Expect(rules[1].Id).To(Equal("#2"))
+		Expect(rules[1].Rule).To(BeIdenticalTo(r2))
+		Expect(rules[2].Id).To(Equal("#1"))
+		Expect(rules[2].Rule).To(BeIdenticalTo(r1))
+	})
+
+	It("replaces named rule", func() {
+		ctx.AddNamedRule("debug", logging.NewConditionRule(logging.DebugLevel, realm))
+		ctx.AddRule(logging.NewConditionRule(logging.TraceLevel, tag))
+		ctx.AddNamedRule("debug", logging.NewConditionRule(logging.TraceLevel, realm))
+
+		rules := ctx.Rules()
+		Expect(len(rules)).To(Equal(2))
+		Expect(rules[0].Id).To(Equal("debug"))
+		Expect(ctx.Logger(realm).Enabled(logging.TraceLevel)).To(BeTrue())
+	})
+
+	It("removes rule", func() {
+		ctx.AddNamedRule("debug", logging.NewConditionRule(logging.DebugLevel, realm))
+		nested := ctx.WithContext(realm)
+		logger := logging.DynamicLogger(nested)
+		Expect(logger.Enabled(logging.DebugLevel)).To(BeTrue())
+
+		Expect(ctx.RemoveRule("debug")).To(BeTrue())
+		Expect(ctx.RemoveRule("debug")).To(BeFalse())
+		Expect(len(ctx.Rules())).To(Equal(0))
+		Expect(logger.Enabled(logging.DebugLevel)).To(BeFalse())
+		Expect(nested.Logger().Enabled(logging.DebugLevel)).To(BeFalse())
+	})
+
+	It("replaces rule at its position", func() {
+		ctx.AddRule(logging.NewConditionRule(logging.DebugLevel, realm))
+		ctx.AddRule(logging.NewConditionRule(logging.WarnLevel, tag))
+		logger := logging.DynamicLogger(ctx, realm)
+		Expect(logger.Enabled(logging.TraceLevel)).To(BeFalse())
+
+		rule := logging.NewConditionRule(logging.TraceLevel, realm)
+		Expect(ctx.ReplaceRule("#1", rule)).To(BeTrue())
+		Expect(ctx.ReplaceRule("#3", rule)).To(BeFalse())
+
+		rules := ctx.Rules()
+		Expect(rules[1].Id).To(Equal("#1"))
+		Expect(rules[1].Rule).To(BeIdenticalTo(rule))
+		Expect(logger.Enabled(logging.TraceLevel)).To(BeTrue())
+	})
+
+	It("keeps names when copying rules", func() {
+		ctx.AddRule(logging.NewConditionRule(logging.DebugLevel, realm))
+		ctx.AddNamedRule("tag", logging.NewConditionRule(logging.WarnLevel, tag))
+
+		other := logging.NewDefault()
+		ctx.AddRulesTo(other)
+		rules := other.Rules()
+		Expect(len(rules)).To(Equal(2))
+		Expect(rules[0].Id).To(Equal("tag"))
+		Expect(rules[1].Id).To(Equal("#1"))
+	})
+
+	It("copies rules with a single modification", func() {
+		ctx.AddRule(logging.NewConditionRule(logging.DebugLevel, realm))
+		ctx.AddNamedRule("tag", logging.NewConditionRule(logging.WarnLevel, tag))
+
+		other := logging.NewDefault()
+		var events []logging.ChangeEvent
+		cancel := other.Watch(func(e logging.ChangeEvent) { events = append(events, e) })
+		defer cancel()
+		watermark := other.Tree().Updater().Watermark()
+
+		ctx.AddRulesTo(other)
+		Expect(other.Tree().Updater().Watermark()).To(Equal(watermark + 1))
+		Expect(len(events)).To(Equal(1))
+		Expect(events[0].Type).To(Equal(logging.ChangeRuleAdded))
+		Expect(events[0].RuleIds).To(Equal([]string{"#1", "tag"}))
+	})
+
+	It("does not generate ids of named rules", func() {
+		ctx.AddNamedRule("#1", logging.NewConditionRule(logging.DebugLevel, realm))
+		ctx.AddRule(logging.NewConditionRule(logging.TraceLevel, tag))
+
+		rules := ctx.Rules()
+		Expect(len(rules)).To(Equal(2))
+		Expect(rules[0].Id).To(Equal("#2"))
+		Expect(rules[1].Id).To(Equal("#1"))
+
+		Expect(ctx.RemoveRule("#2")).To(BeTrue())
+		Expect(ctx.Rules()[0].Id).To(Equal("#1"))
+	})
+
+	Context("replacing rules", func() {
+		It("replaces level and rules", func() {
+			ctx.SetDefaultLevel(logging.WarnLevel)
+			ctx.AddRule(logging.NewConditionRule(logging.TraceLevel, tag))
+			watermark := ctx.Tree().Updater().Watermark()
+			logger := logging.DynamicLogger(ctx, realm)
+			Expect(logger.Enabled(logging.InfoLevel)).To(BeFalse())
+
+			ctx.ReplaceRules(logging.InfoLevel, logging.NewConditionRule(logging.DebugLevel, realm), logging.NewConditionRule(logging.TraceLevel, realm))
+
+			Expect(ctx.Tree().Updater().Watermark()).To(Equal(watermark + 1))
+			Expect(ctx.GetDefaultLevel()).To(Equal(logging.InfoLevel))
+			Expect(len(ctx.Rules())).To(Equal(1))
+			Expect(logger.Enabled(logging.TraceLevel)).To(BeTrue())
+			Expect(ctx.Logger(tag).Enabled(logging.DebugLevel)).To(BeFalse())
+		})
+
+		It("replaces level, rules and filters", func() {
+			ctx.AddFilter(logging.NewDropFilter(logging.NewKeyPresent("other")))
+			watermark := ctx.Tree().Updater().Watermark()
+
+			filter := logging.NewDropFilter(logging.NewKeyPresent("secret"))
+			ctx.ReplaceConfiguration(logging.DebugLevel, []logging.Rule{logging.NewConditionRule(logging.TraceLevel, realm)}, []logging.Filter{filter})
+
+			Expect(ctx.Tree().Updater().Watermark()).To(Equal(watermark + 1))
+			Expect(ctx.GetDefaultLevel()).To(Equal(logging.DebugLevel))
+			Expect(len(ctx.Rules())).To(Equal(1))
+			Expect(ctx.Filters()).To(Equal([]logging.Filter{filter}))
+		})
+
+		It("resets default level", func() {
+			nested := logging.NewWithBase(ctx)
+			ctx.SetDefaultLevel(logging.DebugLevel)
+			nested.SetDefaultLevel(logging.WarnLevel)
+			ctx.ReplaceRules(-1)
+			nested.ReplaceRules(-1)
+			Expect(ctx.GetDefaultLevel()).To(Equal(logging.InfoLevel))
+			Expect(nested.GetDefaultLevel()).To(Equal(logging.InfoLevel))
+			ctx.SetDefaultLevel(logging.DebugLevel)
+			Expect(nested.GetDefaultLevel()).To(Equal(logging.DebugLevel))
+		})
+	})
+})
@@ -48,7 +48,7 @@ var _ = ginkgo.Describe("slice test", func() {
 		ctx := NewWithBase(nil)
 		ctx.AddRule(NewConditionRule(DebugLevel, NewTag("test")))
 		ctx.AddRule(NewConditionRule(TraceLevel, NewTag("other")))
-		gomega.Expect(len(ctx.(*context).rules)).To(gomega.Equal(2))
+		gomega.Expect(len(ctx.(*context).state.Load().rules)).To(gomega.Equal(2))
 		l := ctx.Logger(NewTag("test"))
 		gomega.Expect(l.Enabled(DebugLevel)).To(gomega.BeTrue())
 		gomega.Expect(l.Enabled(TraceLevel)).To(gomega.BeFalse())
@@ -59,7 +59,7 @@ var _ = ginkgo.Describe("slice test", func() {
 		ctx.AddRule(NewConditionRule(DebugLevel, NewTag("test")))
 		ctx.AddRule(NewConditionRule(TraceLevel, NewTag("other")))
 		ctx.AddRule(NewConditionRule(TraceLevel, NewTag("test")))
-		gomega.Expect(len(ctx.(*context).rules)).To(gomega.Equal(2))
+		gomega.Expect(len(ctx.(*context).state.Load().rules)).To(gomega.Equal(2))
 		l := ctx.Logger(NewTag("test"))
 		gomega.Expect(l.Enabled(DebugLevel)).To(gomega.BeTrue())
 		gomega.Expect(l.Enabled(TraceLevel)).To(gomega.BeTrue())
@@ -70,7 +70,7 @@ var _ = ginkgo.Describe("slice test", func() {
 		ctx.AddRule(NewNonReplacableRule(DebugLevel, NewTag("test")))
 		ctx.AddRule(NewConditionRule(TraceLevel, NewTag("other")))
 		ctx.AddRule(NewConditionRule(TraceLevel, NewTag("test")))
-		gomega.Expect(len(ctx.(*context).rules)).To(gomega.Equal(3))
+		gomega.Expect(len(ctx.(*context).state.Load().rules)).To(gomega.Equal(3))
 		l := ctx.Logger(NewTag("test"))
 		gomega.Expect(l.Enabled(DebugLevel)).To(gomega.BeTrue())
 		gomega.Expect(l.Enabled(TraceLevel)).To(gomega.BeTrue())
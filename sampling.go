@@ -0,0 +1,169 @@
+/*
+ * Copyright 2023 Mandelsoft. All rights reserved.
+ *  This file is licensed under the Apache Software License, v. 2 except as noted
+ *  otherwise in the LICENSE file
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package logging
+
+import (
+	"math/rand"
+	"reflect"
+	"sync"
+
+	"github.com/go-logr/logr"
+)
+
+// Sampling describes the sampling of log messages.
+// If both, Every and Ratio, are given, a message must pass both.
+type Sampling struct {
+	// Every passes only one of every n messages of a level.
+	Every int
+	// Ratio passes messages with the given probability (0..1).
+	Ratio float64
+	// Errors enables the sampling for error messages, also.
+	// By default, error messages are always passed.
+	Errors bool
+}
+
+// SamplingRule is a rule enabling a log level for a message context matching
+// all given conditions, but emitting only a fraction of the log messages.
+type SamplingRule struct {
+	conditions []Condition
+	level      int
+	sampling   Sampling
+	state      *samplingState
+}
+
+var _ Rule = (*SamplingRule)(nil)
+var _ UpdatableRule = (*SamplingRule)(nil)
+
+// NewSamplingRule provides a rule enabling the given level for a message
+// context matching all given conditions. All loggers provided by the rule
+// share the sampling state, so the sampling works across all
+// log requests matched by the rule.
+func NewSamplingRule(level int, sampling Sampling, cond ...Condition) Rule {
+	return &SamplingRule{
+		conditions: cond,
+		level:      level,
+		sampling:   sampling,
+		state: &samplingState{
+			sampling: sampling,
+			counters: map[int]uint64{},
+		},
+	}
+}
+
+func (r *SamplingRule) MatchRule(o Rule) bool {
+	if or, ok := o.(*SamplingRule); ok {
+		return reflect.DeepEqual(r.conditions, or.conditions)
+	}
+	return false
+}
+
+func (r *SamplingRule) Match(sink SinkFunc, messageContext ...MessageContext) Logger {
+	for _, c := range r.conditions {
+		if !c.Match(messageContext...) {
+			return nil
+		}
+	}
+
+	return NewLogger(&samplingSink{r.state, withCallDepth(DynSink(AsLevelFunc(r.level), 0, sink), 1)})
+}
+
+func (r *SamplingRule) Level() int {
+	return r.level
+}
+
+func (r *SamplingRule) Conditions() []Condition {
+	return sliceCopy(r.conditions)
+}
+
+func (r *SamplingRule) Sampling() Sampling {
+	return r.sampling
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+type samplingState struct {
+	sampling Sampling
+	lock     sync.Mutex
+	counters map[int]uint64
+}
+
+func (s *samplingState) pass(level int) bool {
+	if s.sampling.Every > 1 {
+		s.lock.Lock()
+		cnt := s.counters[level]
+		s.counters[level] = cnt + 1
+		s.lock.Unlock()
+		if cnt%uint64(s.sampling.Every) != 0 {
+			return false
+		}
+	}
+	if s.sampling.Ratio > 0 && s.sampling.Ratio < 1 {
+		return rand.Float64() < s.sampling.Ratio
+	}
+	return true
+}
+
+type samplingSink struct {
+	state *samplingState
+	sink  logr.LogSink
+}
+
+var _ logr.CallDepthLogSink = (*samplingSink)(nil)
+var _ filteringSink = (*samplingSink)(nil)
+
+func (s *samplingSink) Init(info logr.RuntimeInfo) {
+}
+
+func (s *samplingSink) Enabled(level int) bool {
+	return s.sink.Enabled(level)
+}
+
+func (s *samplingSink) Info(level int, msg string, keysAndValues ...interface{}) {
+	if !s.sink.Enabled(level) || !s.state.pass(level) {
+		return
+	}
+	s.sink.Info(level, msg, keysAndValues...)
+}
+
+func (s *samplingSink) Error(err error, msg string, keysAndValues ...interface{}) {
+	if s.state.sampling.Errors && !s.state.pass(ErrorLevel) {
+		return
+	}
+	s.sink.Error(err, msg, keysAndValues...)
+}
+
+func (s *samplingSink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	return &samplingSink{s.state, s.sink.WithValues(keysAndValues...)}
+}
+
+func (s *samplingSink) WithName(name string) logr.LogSink {
+	return &samplingSink{s.state, s.sink.WithName(name)}
+}
+
+func (s *samplingSink) WithCallDepth(depth int) logr.LogSink {
+	return &samplingSink{s.state, withCallDepth(s.sink, depth)}
+}
+
+func (s *samplingSink) Unwrap() logr.LogSink {
+	return s.sink
+}
+
+func (s *samplingSink) unfiltered() logr.LogSink {
+	return s.sink
+}
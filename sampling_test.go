@@ -0,0 +1,87 @@
+/*
+ * Copyright 2023 Mandelsoft. All rights reserved.
+ *  This file is licensed under the Apache Software License, v. 2 except as noted
+ *  otherwise in the LICENSE file
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package logging_test
+
+import (
+	"bytes"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/tonglil/buflogr"
+
+	"github.com/mandelsoft/logging"
+)
+
+var _ = Describe("sampling rules", func() {
+	var buf bytes.Buffer
+	var ctx logging.Context
+
+	realm := logging.NewRealm("realm")
+
+	BeforeEach(func() {
+		buf.Reset()
+		ctx = logging.New(buflogr.NewWithBuffer(&buf))
+	})
+
+	It("passes one of every n messages", func() {
+		ctx.AddRule(logging.NewSamplingRule(logging.DebugLevel, logging.Sampling{Every: 3}, realm))
+
+		for i := 0; i < 7; i++ {
+			ctx.Logger(realm).Debug(fmt.Sprintf("debug %d", i))
+			ctx.Logger(realm).Info(fmt.Sprintf("info %d", i))
+		}
+		ctx.Logger(realm).Trace("trace")
+		ctx.Logger(realm).Error("error")
+
+		Expect("\n" + buf.String()).To(Equal(`
+V[4] debug 0 realm realm
+V[3] info 0 realm realm
+V[4] debug 3 realm realm
+V[3] info 3 realm realm
+V[4] debug 6 realm realm
+V[3] info 6 realm realm
+ERROR <nil> error realm realm
+`))
+	})
+
+	It("samples errors", func() {
+		ctx.AddRule(logging.NewSamplingRule(logging.DebugLevel, logging.Sampling{Every: 2, Errors: true}, realm))
+
+		for i := 0; i < 3; i++ {
+			ctx.Logger(realm).Error(fmt.Sprintf("error %d", i))
+		}
+
+		Expect("\n" + buf.String()).To(Equal(`
+ERROR <nil> error 0 realm realm
+ERROR <nil> error 2 realm realm
+`))
+	})
+
+	It("samples with ratio", func() {
+		ctx.AddRule(logging.NewSamplingRule(logging.DebugLevel, logging.Sampling{Ratio: 0.5}, realm))
+
+		logger := ctx.Logger(realm)
+		for i := 0; i < 1000; i++ {
+			logger.Debug("debug")
+		}
+		Expect(bytes.Count(buf.Bytes(), []byte("\n"))).To(BeNumerically("~", 500, 150))
+	})
+})
@@ -21,6 +21,7 @@ package scheme
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"sync"
 
 	"sigs.k8s.io/yaml"
@@ -66,6 +67,32 @@ func (s *Scheme[T, F]) Register(name string, proto Factory[T, F]) {
 	s.prototypes[name] = t
 }
 
+// Types returns the names of the registered types in alphabetical order.
+func (s *Scheme[T, F]) Types() []string {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	names := make([]string, 0, len(s.prototypes))
+	for n := range s.prototypes {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Prototype returns a new instance of a registered type.
+// If there is no such type, nil is returned.
+func (s *Scheme[T, F]) Prototype(name string) Factory[T, F] {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	t := s.prototypes[name]
+	if t == nil {
+		return nil
+	}
+	return reflect.New(t).Interface().(Factory[T, F])
+}
+
 func (s *Scheme[T, F]) Get(data []byte) (T, error) {
 	var zero T
 	var e Element[Factory[T, F]]
@@ -28,13 +28,13 @@ type sink struct {
 	sink  logr.LogSink
 }
 
-var _ logr.LogSink = (*sink)(nil)
+var _ logr.CallDepthLogSink = (*sink)(nil)
 
 func WrapSink(level, delta int, orig logr.LogSink) logr.LogSink {
 	return &sink{
 		level: level,
 		delta: delta,
-		sink:  orig,
+		sink:  withCallDepth(orig, 1),
 	}
 }
 
@@ -42,8 +42,10 @@ func (s *sink) Unwrap() logr.LogSink {
 	return s.sink
 }
 
+// Init does not forward the runtime info, the wrapped sink
+// is already initialized and the call depth is adjusted
+// for the frame added by this wrapper.
 func (s *sink) Init(info logr.RuntimeInfo) {
-	s.sink.Init(info)
 }
 
 func (s *sink) Enabled(level int) bool {
@@ -81,6 +83,14 @@ func (s sink) WithName(name string) logr.LogSink {
 	}
 }
 
+func (s *sink) WithCallDepth(depth int) logr.LogSink {
+	return &sink{
+		level: s.level,
+		delta: s.delta,
+		sink:  withCallDepth(s.sink, depth),
+	}
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 
 func AsLevelFunc(lvl int) LevelFunc {
@@ -98,10 +108,11 @@ func AsSinkFunc(s logr.LogSink) SinkFunc {
 type dynsink struct {
 	level LevelFunc
 	delta int
+	depth int
 	sink  SinkFunc
 }
 
-var _ logr.LogSink = (*sink)(nil)
+var _ logr.CallDepthLogSink = (*dynsink)(nil)
 
 func DynSink(level LevelFunc, delta int, orig SinkFunc) logr.LogSink {
 	return &dynsink{
@@ -111,8 +122,9 @@ func DynSink(level LevelFunc, delta int, orig SinkFunc) logr.LogSink {
 	}
 }
 
+// Init does not forward the runtime info, the provided sinks
+// are already initialized.
 func (s *dynsink) Init(info logr.RuntimeInfo) {
-	s.sink().Init(info)
 }
 
 func (s *dynsink) Enabled(level int) bool {
@@ -127,17 +139,24 @@ func (s *dynsink) Info(level int, msg string, keysAndValues ...interface{}) {
 	if !s.Enabled(level) {
 		return
 	}
-	s.sink().Info(level+s.delta, msg, keysAndValues...)
+	s.target().Info(level+s.delta, msg, keysAndValues...)
 }
 
 func (s *dynsink) Error(err error, msg string, keysAndValues ...interface{}) {
-	s.sink().Error(err, msg, keysAndValues...)
+	s.target().Error(err, msg, keysAndValues...)
+}
+
+// target provides the actual sink with a call depth
+// adjusted for the frame added by this wrapper.
+func (s *dynsink) target() logr.LogSink {
+	return withCallDepth(s.sink(), s.depth+1)
 }
 
 func (s *dynsink) WithValues(keysAndValues ...interface{}) logr.LogSink {
 	return &dynsink{
 		level: s.level,
 		delta: s.delta,
+		depth: s.depth,
 		sink:  func() logr.LogSink { return s.sink().WithValues(keysAndValues...) },
 	}
 }
@@ -146,14 +165,36 @@ func (s *dynsink) WithName(name string) logr.LogSink {
 	return &dynsink{
 		level: s.level,
 		delta: s.delta,
+		depth: s.depth,
 		sink:  func() logr.LogSink { return s.sink().WithName(name) },
 	}
 }
 
+func (s *dynsink) WithCallDepth(depth int) logr.LogSink {
+	return &dynsink{
+		level: s.level,
+		delta: s.delta,
+		depth: s.depth + depth,
+		sink:  s.sink,
+	}
+}
+
 func (s *dynsink) Unwrap() logr.LogSink {
 	return s.sink()
 }
 
+// withCallDepth adds the given number of stack frames
+// to the call depth of a sink, if supported.
+func withCallDepth(s logr.LogSink, depth int) logr.LogSink {
+	if depth == 0 {
+		return s
+	}
+	if c, ok := s.(logr.CallDepthLogSink); ok {
+		return c.WithCallDepth(depth)
+	}
+	return s
+}
+
 // UnwrapLogSink return the original (unmapped)
 // logr.LogSink.
 func UnwrapLogSink(s logr.LogSink) logr.LogSink {
@@ -0,0 +1,177 @@
+/*
+ * Copyright 2023 Mandelsoft. All rights reserved.
+ *  This file is licensed under the Apache Software License, v. 2 except as noted
+ *  otherwise in the LICENSE file
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+// Package slogh provides an [slog.Handler] backed by a
+// [github.com/mandelsoft/logging] logging context.
+// The activation of log records is determined by the
+// rule set of the logging context.
+//
+// Example:
+//
+//	logger := slog.New(slogh.New(ctx))
+//	logger.Info("message", "realm", "my/realm", "key", "value")
+package slogh
+
+import (
+	gocontext "context"
+	"log/slog"
+
+	"github.com/mandelsoft/logging"
+)
+
+// FieldKeyLogger is the default attribute key mapped to
+// a logging.Name message context.
+const FieldKeyLogger = "logger"
+
+// Option is an option to configure a handler.
+type Option func(h *handler)
+
+// WithRealmKey sets the attribute key, whose value is mapped to
+// a logging.Realm message context. The default is logging.FieldKeyRealm.
+// An empty key disables the mapping.
+func WithRealmKey(key string) Option {
+	return func(h *handler) {
+		h.realmKey = key
+	}
+}
+
+// WithNameKey sets the attribute key, whose value is mapped to
+// a logging.Name message context. The default is FieldKeyLogger.
+// An empty key disables the mapping.
+func WithNameKey(key string) Option {
+	return func(h *handler) {
+		h.nameKey = key
+	}
+}
+
+type handler struct {
+	actx     logging.AttributionContext
+	realmKey string
+	nameKey  string
+	prefix   string
+}
+
+var _ slog.Handler = (*handler)(nil)
+
+// New returns an slog.Handler issuing log records with loggers
+// provided by the given logging context.
+// Attributes given at top-level with the realm or name key (see WithRealmKey
+// and WithNameKey) are used as message context for the rule evaluation.
+// All other attributes are passed as key/value pairs.
+// Groups are mapped to key prefixes separated by a dot.
+//
+// Because an slog.Logger checks the activation before the attributes
+// of a log record are known, the activation is initially determined
+// for the message context of the handler. Therefore, a message context
+// enabling a log level should be set with slog.Logger.With. Message contexts
+// given with a log record are only able to restrict the activation.
+func New(ctxp logging.AttributionContextProvider, opts ...Option) slog.Handler {
+	h := &handler{
+		actx:     ctxp.AttributionContext(),
+		realmKey: logging.FieldKeyRealm,
+		nameKey:  FieldKeyLogger,
+	}
+	for _, o := range opts {
+		o(h)
+	}
+	return h
+}
+
+// MapLevel maps an slog.Level to the level of the logging library.
+func MapLevel(level slog.Level) int {
+	switch {
+	case level >= slog.LevelError:
+		return logging.ErrorLevel
+	case level >= slog.LevelWarn:
+		return logging.WarnLevel
+	case level >= slog.LevelInfo:
+		return logging.InfoLevel
+	case level >= slog.LevelDebug:
+		return logging.DebugLevel
+	default:
+		return logging.TraceLevel
+	}
+}
+
+func (h *handler) Enabled(ctx gocontext.Context, level slog.Level) bool {
+	return h.actx.Logger().Enabled(MapLevel(level))
+}
+
+func (h *handler) Handle(ctx gocontext.Context, r slog.Record) error {
+	var mctx []logging.MessageContext
+	keypairs := make([]interface{}, 0, 2*r.NumAttrs())
+
+	r.Attrs(func(a slog.Attr) bool {
+		mctx, keypairs = h.convert(mctx, keypairs, h.prefix, a)
+		return true
+	})
+
+	level := MapLevel(r.Level)
+	logger := h.actx.Logger(mctx...)
+	if logger.Enabled(level) {
+		logger.Log(level, r.Message, keypairs...)
+	}
+	return nil
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var mctx []logging.MessageContext
+	var keypairs []interface{}
+
+	for _, a := range attrs {
+		mctx, keypairs = h.convert(mctx, keypairs, h.prefix, a)
+	}
+	n := *h
+	n.actx = h.actx.WithContext(mctx...).WithValues(keypairs...)
+	return &n
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	n := *h
+	n.prefix = h.prefix + name + "."
+	return &n
+}
+
+// convert maps an attribute to a message context or key/value pairs.
+func (h *handler) convert(mctx []logging.MessageContext, keypairs []interface{}, prefix string, a slog.Attr) ([]logging.MessageContext, []interface{}) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return mctx, keypairs
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix = prefix + a.Key + "."
+		}
+		for _, g := range a.Value.Group() {
+			mctx, keypairs = h.convert(mctx, keypairs, prefix, g)
+		}
+		return mctx, keypairs
+	}
+	if prefix == "" && a.Value.Kind() == slog.KindString {
+		switch a.Key {
+		case "":
+		case h.realmKey:
+			return append(mctx, logging.NewRealm(a.Value.String())), keypairs
+		case h.nameKey:
+			return append(mctx, logging.NewName(a.Value.String())), keypairs
+		}
+	}
+	return mctx, append(keypairs, prefix+a.Key, a.Value.Any())
+}
@@ -0,0 +1,95 @@
+/*
+ * Copyright 2023 Mandelsoft. All rights reserved.
+ *  This file is licensed under the Apache Software License, v. 2 except as noted
+ *  otherwise in the LICENSE file
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+package slogh_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/tonglil/buflogr"
+
+	"github.com/mandelsoft/logging"
+	"github.com/mandelsoft/logging/slogh"
+)
+
+var _ = Describe("slog handler", func() {
+	var buf bytes.Buffer
+	var ctx logging.Context
+	var logger *slog.Logger
+
+	BeforeEach(func() {
+		buf.Reset()
+		ctx = logging.New(buflogr.NewWithBuffer(&buf))
+		logger = slog.New(slogh.New(ctx))
+	})
+
+	It("maps levels", func() {
+		Expect(slogh.MapLevel(slog.LevelError + 4)).To(Equal(logging.ErrorLevel))
+		Expect(slogh.MapLevel(slog.LevelError)).To(Equal(logging.ErrorLevel))
+		Expect(slogh.MapLevel(slog.LevelWarn)).To(Equal(logging.WarnLevel))
+		Expect(slogh.MapLevel(slog.LevelInfo)).To(Equal(logging.InfoLevel))
+		Expect(slogh.MapLevel(slog.LevelInfo + 1)).To(Equal(logging.InfoLevel))
+		Expect(slogh.MapLevel(slog.LevelDebug)).To(Equal(logging.DebugLevel))
+		Expect(slogh.MapLevel(slog.LevelDebug - 1)).To(Equal(logging.TraceLevel))
+	})
+
+	It("logs according to default level", func() {
+		logger.Debug("debug")
+		logger.Info("info", "key", "value")
+		logger.Warn("warn")
+		logger.Error("error")
+		Expect(buf.String()).To(Equal("V[3] info key value\nV[2] warn\nERROR <nil> error\n"))
+		Expect(logger.Enabled(context.TODO(), slog.LevelDebug)).To(BeFalse())
+		Expect(logger.Enabled(context.TODO(), slog.LevelInfo)).To(BeTrue())
+	})
+
+	It("maps realm and name attributes to message context", func() {
+		ctx.AddRule(logging.NewConditionRule(logging.ErrorLevel, logging.NewRealm("quiet")))
+
+		logger.Info("info", "realm", "quiet", "key", "value")
+		logger.Info("info", "realm", "realm", "key", "value")
+		logger.Info("info", "logger", "name")
+		Expect(buf.String()).To(Equal("V[3] info realm realm key value\nV[3] name info\n"))
+	})
+
+	It("maps attributes to values", func() {
+		ctx.AddRule(logging.NewConditionRule(logging.DebugLevel, logging.NewRealm("realm")))
+
+		l := logger.With("realm", "realm", "key", "value")
+		Expect(l.Enabled(context.TODO(), slog.LevelDebug)).To(BeTrue())
+		l.Debug("debug", "other", 1)
+		Expect(buf.String()).To(Equal("V[4] debug realm realm key value other 1\n"))
+	})
+
+	It("maps groups to prefixed keys", func() {
+		logger.WithGroup("group").With("realm", "realm").Info("info", slog.Group("sub", "key", "value"), "other", true)
+		Expect(buf.String()).To(Equal("V[3] info group.realm realm group.sub.key value group.other true\n"))
+	})
+
+	It("uses configured keys", func() {
+		logger = slog.New(slogh.New(ctx, slogh.WithRealmKey("component"), slogh.WithNameKey("")))
+		ctx.AddRule(logging.NewConditionRule(logging.DebugLevel, logging.NewRealm("realm")))
+
+		logger.With("component", "realm").Debug("debug", "logger", "name")
+		Expect(buf.String()).To(Equal("V[4] debug realm realm logger name\n"))
+	})
+})
@@ -0,0 +1,244 @@
+/*
+ * Copyright 2023 Mandelsoft. All rights reserved.
+ *  This file is licensed under the Apache Software License, v. 2 except as noted
+ *  otherwise in the LICENSE file
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+// Package slogr provides a [logr.LogSink] writing log records
+// to an [slog.Handler]. It can be used as base logger for a
+// [github.com/mandelsoft/logging] logging context.
+//
+// Example:
+//
+//	ctx := logging.New(slogr.NewJSON(os.Stderr, nil))
+package slogr
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/mandelsoft/logging/utils"
+)
+
+// FieldKeyLogger is the name of the attribute used to store the
+// logr logger name.
+const FieldKeyLogger = "logger"
+
+// FieldKeyError is the name of the attribute used to store the
+// error passed to Error.
+const FieldKeyError = "error"
+
+// LevelAll is the handler level used by NewJSON and NewText, if no
+// level is given. It leaves the activation of log records to the
+// logging context.
+const LevelAll = slog.Level(math.MinInt)
+
+// infoLevel is the logr level mapped to slog.LevelInfo.
+// This is the logging.InfoLevel.
+const infoLevel = 3
+
+// SlogLevel maps a logr level to the slog level used to issue
+// log records. The logr level 3 (logging.InfoLevel) is mapped to
+// slog.LevelInfo, every level step is mapped to a difference of 4
+// of the slog level (the distance between the standard slog levels).
+func SlogLevel(level int) slog.Level {
+	return slog.Level(4 * (infoLevel - level))
+}
+
+// Option is an option to give when constructing an slog based logger.
+type Option func(s *sink)
+
+// WithWriter sets the writer used by the handler. It is provided
+// as technical log writer (see logwriter.DetermineLogWriter).
+func WithWriter(w io.Writer) Option {
+	return func(s *sink) {
+		s.writer = w
+	}
+}
+
+// WithName will set an initial name instead of having to call `WithName` on the
+// logger itself after constructing it.
+func WithName(name ...string) Option {
+	return func(s *sink) {
+		s.name = name
+	}
+}
+
+type sink struct {
+	handler slog.Handler
+	writer  io.Writer
+	name    []string
+	values  []interface{}
+	depth   int
+}
+
+var _ logr.CallDepthLogSink = (*sink)(nil)
+
+// New returns a logr.Logger writing log records to the given slog.Handler.
+func New(h slog.Handler, opts ...Option) logr.Logger {
+	s := &sink{
+		handler: h,
+	}
+	for _, o := range opts {
+		o(s)
+	}
+	return logr.New(s)
+}
+
+// NewJSON returns a logr.Logger writing log records with an slog.JSONHandler.
+// If no level is configured by the options, LevelAll is used.
+func NewJSON(w io.Writer, opts *slog.HandlerOptions) logr.Logger {
+	return New(slog.NewJSONHandler(w, defaultOptions(opts)), WithWriter(w))
+}
+
+// NewText returns a logr.Logger writing log records with an slog.TextHandler.
+// If no level is configured by the options, LevelAll is used.
+func NewText(w io.Writer, opts *slog.HandlerOptions) logr.Logger {
+	return New(slog.NewTextHandler(w, defaultOptions(opts)), WithWriter(w))
+}
+
+func defaultOptions(opts *slog.HandlerOptions) *slog.HandlerOptions {
+	if opts == nil {
+		opts = &slog.HandlerOptions{}
+	}
+	if opts.Level == nil {
+		n := *opts
+		n.Level = LevelAll
+		opts = &n
+	}
+	return opts
+}
+
+// Handler returns the slog.Handler used by a logr.LogSink
+// provided by this package.
+func Handler(s logr.LogSink) (slog.Handler, bool) {
+	if l, ok := s.(*sink); ok {
+		return l.handler, true
+	}
+	return nil, false
+}
+
+// Init receives optional information about the library.
+func (s *sink) Init(ri logr.RuntimeInfo) {
+	s.depth = ri.CallDepth
+}
+
+func (s *sink) Enabled(level int) bool {
+	return s.handler.Enabled(context.Background(), SlogLevel(level))
+}
+
+func (s *sink) Info(level int, msg string, keysAndValues ...interface{}) {
+	s.handle(SlogLevel(level), nil, msg, keysAndValues)
+}
+
+func (s *sink) Error(err error, msg string, keysAndValues ...interface{}) {
+	s.handle(slog.LevelError, err, msg, keysAndValues)
+}
+
+func (s *sink) handle(level slog.Level, err error, msg string, keysAndValues []interface{}) {
+	ctx := context.Background()
+	if !s.handler.Enabled(ctx, level) {
+		return
+	}
+
+	var pcs [1]uintptr
+	// +1 for runtime.Callers
+	// +1 for this frame
+	// +1 for frame calling here (Info/Error)
+	// +depth for the frames between the sink and the end-user
+	runtime.Callers(s.depth+3, pcs[:])
+
+	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	if len(s.name) > 0 {
+		r.AddAttrs(slog.String(FieldKeyLogger, strings.Join(s.name, ".")))
+	}
+	if err != nil {
+		r.AddAttrs(slog.Any(FieldKeyError, err))
+	}
+	r.AddAttrs(attrs(s.values)...)
+	r.AddAttrs(attrs(keysAndValues)...)
+	s.handler.Handle(ctx, r)
+}
+
+// WithValues returns a new sink with additional key/values pairs.
+// Like logrus fields, values replace former values with the same key,
+// for example, the realm field of nested realms.
+func (s *sink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	n := *s
+	n.values = make([]interface{}, len(s.values), len(s.values)+len(keysAndValues))
+	copy(n.values, s.values)
+outer:
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		k := key(keysAndValues[i])
+		v := utils.Resolve(keysAndValues[i+1])
+		for j := 0; j < len(n.values); j += 2 {
+			if n.values[j] == k {
+				n.values[j+1] = v
+				continue outer
+			}
+		}
+		n.values = append(n.values, k, v)
+	}
+	return &n
+}
+
+// WithName returns a new sink with an extended name. The name
+// is stored as attribute with key FieldKeyLogger.
+func (s *sink) WithName(name string) logr.LogSink {
+	n := *s
+	n.name = append(s.name[:len(s.name):len(s.name)], name)
+	return &n
+}
+
+// WithCallDepth offsets the call stack used to determine the source
+// location of log records.
+func (s *sink) WithCallDepth(depth int) logr.LogSink {
+	n := *s
+	n.depth += depth
+	return &n
+}
+
+// LogWriter provides the writer used by the handler, if known.
+func (s *sink) LogWriter() io.Writer {
+	return s.writer
+}
+
+// attrs converts a key/value list into slog attributes.
+// Lazy values are resolved and ignored values omitted.
+func attrs(keysAndValues []interface{}) []slog.Attr {
+	var r []slog.Attr
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		v := utils.Resolve(keysAndValues[i+1])
+		if v == utils.Ignore {
+			continue
+		}
+		r = append(r, slog.Any(key(keysAndValues[i]), v))
+	}
+	return r
+}
+
+func key(k interface{}) string {
+	if s, ok := k.(string); ok {
+		return s
+	}
+	return fmt.Sprint(k)
+}
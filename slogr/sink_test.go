@@ -0,0 +1,111 @@
+/*
+ * Copyright 2023 Mandelsoft. All rights reserved.
+ *  This file is licensed under the Apache Software License, v. 2 except as noted
+ *  otherwise in the LICENSE file
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+package slogr_test
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"runtime"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/mandelsoft/logging"
+	"github.com/mandelsoft/logging/logwriter"
+	"github.com/mandelsoft/logging/slogr"
+)
+
+func dropTime(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) == 0 && a.Key == slog.TimeKey {
+		return slog.Attr{}
+	}
+	return a
+}
+
+var _ = Describe("slog sink", func() {
+	var buf bytes.Buffer
+	var ctx logging.Context
+
+	realm := logging.NewRealm("realm")
+
+	BeforeEach(func() {
+		buf.Reset()
+		ctx = logging.New(slogr.NewJSON(&buf, &slog.HandlerOptions{ReplaceAttr: dropTime}))
+	})
+
+	It("maps levels", func() {
+		Expect(slogr.SlogLevel(logging.ErrorLevel)).To(Equal(slog.LevelError))
+		Expect(slogr.SlogLevel(logging.WarnLevel)).To(Equal(slog.LevelWarn))
+		Expect(slogr.SlogLevel(logging.InfoLevel)).To(Equal(slog.LevelInfo))
+		Expect(slogr.SlogLevel(logging.DebugLevel)).To(Equal(slog.LevelDebug))
+		Expect(slogr.SlogLevel(logging.TraceLevel)).To(Equal(slog.LevelDebug - 4))
+	})
+
+	It("logs according to rules", func() {
+		ctx.AddRule(logging.NewConditionRule(logging.TraceLevel, realm))
+		ctx.Logger().Debug("debug")
+		ctx.Logger().Info("info", "key", "value")
+		ctx.Logger(realm).Trace("trace")
+		Expect(buf.String()).To(Equal(`{"level":"INFO","msg":"info","key":"value"}
+{"level":"DEBUG-4","msg":"trace","realm":"realm"}
+`))
+	})
+
+	It("logs errors", func() {
+		ctx.Logger().Error("error", "key", "value")
+		ctx.Logger().LogError(fmt.Errorf("failed"), "error")
+		Expect(buf.String()).To(Equal(`{"level":"ERROR","msg":"error","key":"value"}
+{"level":"ERROR","msg":"error","error":"failed"}
+`))
+	})
+
+	It("keeps names and values", func() {
+		logger := ctx.Logger(logging.NewName("name"), realm, logging.NewRealm("nested")).WithName("sub").WithValues("key", "value")
+		logger.Info("info", "other", logging.Lazy(func() interface{} { return 1 }))
+		Expect(buf.String()).To(Equal(`{"level":"INFO","msg":"info","logger":"name.sub","realm":"nested","key":"value","other":1}
+`))
+	})
+
+	It("keeps level shift", func() {
+		ctx = logging.New(slogr.NewJSON(&buf, &slog.HandlerOptions{ReplaceAttr: dropTime}).V(1))
+		ctx.Logger().Info("info")
+		Expect(buf.String()).To(Equal(`{"level":"DEBUG","msg":"info"}
+`))
+	})
+
+	It("respects handler level", func() {
+		ctx = logging.New(slogr.NewJSON(&buf, &slog.HandlerOptions{Level: slog.LevelWarn, ReplaceAttr: dropTime}))
+		ctx.Logger().Info("info")
+		ctx.Logger().Warn("warn")
+		Expect(buf.String()).To(Equal(`{"level":"WARN","msg":"warn"}
+`))
+	})
+
+	It("determines log writer", func() {
+		Expect(ctx.Tree().LogWriter()).To(BeIdenticalTo(&buf))
+		Expect(logwriter.DetermineLogWriter(slogr.New(slog.NewTextHandler(&buf, nil)).GetSink())).To(BeNil())
+	})
+
+	It("reports source", func() {
+		ctx = logging.New(slogr.NewText(&buf, &slog.HandlerOptions{AddSource: true, ReplaceAttr: dropTime}))
+		_, file, line, _ := runtime.Caller(0)
+		ctx.Logger().Info("info")
+		Expect(buf.String()).To(Equal(fmt.Sprintf("level=INFO source=%s:%d msg=info\n", file, line+1)))
+	})
+})
@@ -0,0 +1,153 @@
+/*
+ * Copyright 2023 Mandelsoft. All rights reserved.
+ *  This file is licensed under the Apache Software License, v. 2 except as noted
+ *  otherwise in the LICENSE file
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package logging
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// Snapshot is an opaque snapshot of the configuration of a
+// logging context (see Context.Snapshot).
+type Snapshot interface {
+	snapshot()
+}
+
+type snapshot struct {
+	ctx   *context
+	state *state
+}
+
+func (s *snapshot) snapshot() {}
+
+func (c *context) Snapshot() Snapshot {
+	return &snapshot{c, c.state.Load()}
+}
+
+func (c *context) Restore(s Snapshot) error {
+	snap, ok := s.(*snapshot)
+	if !ok || snap.ctx != c {
+		return fmt.Errorf("snapshot does not belong to logging context %s", describeContext(c))
+	}
+
+	c.lock.Lock()
+	defer c.unlock()
+
+	old := c.state.Load()
+	n := snap.state.copy()
+	// effective settings must be determined again,
+	// because the base context might have been changed.
+	n.seen = old.seen
+	// rules expired in the meantime are not restored.
+	now := time.Now()
+	for i := 0; i < len(n.rules); {
+		if t, ok := n.rules[i].Rule.(TemporaryRule); ok && t.Expired(now) {
+			n.rules = append(n.rules[:i:i], n.rules[i+1:]...)
+			n.index = nil
+		} else {
+			i++
+		}
+	}
+	c.publish(n)
+	c.scheduleRuleChange(n)
+
+	if old.level != n.level {
+		c.changed(ChangeDefaultLevel, func(e *ChangeEvent) { e.Level = n.level })
+	}
+	if old.sink != n.sink || old.writer != n.writer {
+		c.changed(ChangeBaseLogger)
+	}
+	if !sameFilters(old.filters, n.filters) {
+		c.changed(ChangeFilters)
+	}
+	for name := range old.sinks {
+		if old.sinks[name] != n.sinks[name] {
+			c.changed(ChangeNamedSink, func(e *ChangeEvent) { e.SinkName = name })
+		}
+	}
+	for name := range n.sinks {
+		if old.sinks[name] == nil {
+			c.changed(ChangeNamedSink, func(e *ChangeEvent) { e.SinkName = name })
+		}
+	}
+	c.rulesChanged(ChangeRuleRemoved, missingRules(old.rules, n.rules))
+	c.rulesChanged(ChangeRuleAdded, missingRules(n.rules, old.rules))
+	return nil
+}
+
+// missingRules returns the ids of the rules of the first rule set,
+// which are not part of the second one.
+func missingRules(rules []RuleEntry, other []RuleEntry) []string {
+	var ids []string
+outer:
+	for _, r := range rules {
+		for _, o := range other {
+			if r.Id == o.Id && sameRule(r.Rule, o.Rule) {
+				continue outer
+			}
+		}
+		ids = append(ids, r.Id)
+	}
+	return ids
+}
+
+func sameFilters(a, b []Filter) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if reflect.TypeOf(a[i]) != reflect.TypeOf(b[i]) {
+			return false
+		}
+		if !reflect.TypeOf(a[i]).Comparable() || a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func sameRule(a, b Rule) bool {
+	if reflect.TypeOf(a) != reflect.TypeOf(b) {
+		return false
+	}
+	if !reflect.TypeOf(a).Comparable() {
+		return reflect.DeepEqual(a, b)
+	}
+	return a == b
+}
+
+// PreserveDefaultContext captures the actual default context
+// together with its configuration. The returned function restores
+// the default context setting and the configuration of this context.
+// It is intended to be used in test suites to prevent leaking
+// configuration changes between tests, for example with
+// Ginkgo by
+//
+//	BeforeEach(func() {
+//		DeferCleanup(logging.PreserveDefaultContext())
+//	})
+func PreserveDefaultContext() func() {
+	ctx := defaultContext.Context
+	s := ctx.Snapshot()
+	return func() {
+		defaultContext.Context = ctx
+		ctx.Restore(s)
+	}
+}
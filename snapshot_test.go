@@ -0,0 +1,122 @@
+/*
+ * Copyright 2023 Mandelsoft. All rights reserved.
+ *  This file is licensed under the Apache Software License, v. 2 except as noted
+ *  otherwise in the LICENSE file
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package logging_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/tonglil/buflogr"
+
+	"github.com/mandelsoft/logging"
+)
+
+var _ = Describe("snapshots", func() {
+	var buf bytes.Buffer
+	var ctx logging.Context
+
+	realm := logging.NewRealm("realm")
+	tag := logging.NewTag("tag")
+
+	BeforeEach(func() {
+		buf.Reset()
+		ctx = logging.New(buflogr.NewWithBuffer(&buf))
+	})
+
+	It("restores configuration", func() {
+		ctx.AddRule(logging.NewConditionRule(logging.DebugLevel, realm))
+		ctx.AddNamedRule("tag", logging.NewConditionRule(logging.WarnLevel, tag))
+		sink := ctx.GetSink()
+		rules := ctx.Rules()
+
+		s := ctx.Snapshot()
+
+		ctx.SetDefaultLevel(logging.TraceLevel)
+		ctx.ResetRules()
+		ctx.AddRule(logging.NewConditionRule(logging.ErrorLevel, realm))
+		ctx.SetBaseLogger(buflogr.NewWithBuffer(&bytes.Buffer{}))
+		ctx.SetNamedSink("sink", buflogr.NewWithBuffer(&buf))
+
+		Expect(ctx.Restore(s)).To(Succeed())
+		Expect(ctx.GetDefaultLevel()).To(Equal(logging.InfoLevel))
+		Expect(ctx.GetSink()).To(BeIdenticalTo(sink))
+		Expect(ctx.GetNamedSink("sink")).To(BeNil())
+		Expect(ctx.Rules()).To(Equal(rules))
+		Expect(ctx.Logger(realm).Enabled(logging.DebugLevel)).To(BeTrue())
+		Expect(ctx.Logger(tag).Enabled(logging.InfoLevel)).To(BeFalse())
+	})
+
+	It("updates loggers", func() {
+		nested := logging.NewWithBase(ctx)
+		logger := logging.DynamicLogger(nested, realm)
+		s := ctx.Snapshot()
+
+		ctx.AddRule(logging.NewConditionRule(logging.DebugLevel, realm))
+		Expect(logger.Enabled(logging.DebugLevel)).To(BeTrue())
+		watermark := ctx.Tree().Updater().Watermark()
+
+		ctx.Restore(s)
+		Expect(ctx.Tree().Updater().Watermark()).To(BeNumerically(">", watermark))
+		Expect(logger.Enabled(logging.DebugLevel)).To(BeFalse())
+	})
+
+	It("reports changes", func() {
+		var events []logging.ChangeEvent
+		ctx.AddRule(logging.NewConditionRule(logging.DebugLevel, realm))
+		s := ctx.Snapshot()
+
+		ctx.AddRule(logging.NewConditionRule(logging.TraceLevel, tag))
+		ctx.SetDefaultLevel(logging.DebugLevel)
+
+		cancel := ctx.Watch(func(e logging.ChangeEvent) { events = append(events, e) })
+		defer cancel()
+		ctx.Restore(s)
+		Expect(len(events)).To(Equal(2))
+		Expect(events[0].Type).To(Equal(logging.ChangeDefaultLevel))
+		Expect(events[0].Level).To(Equal(logging.InfoLevel))
+		Expect(events[1].Type).To(Equal(logging.ChangeRuleRemoved))
+		Expect(events[1].RuleIds).To(Equal([]string{"#2"}))
+	})
+
+	It("rejects foreign snapshots", func() {
+		other := logging.New(buflogr.NewWithBuffer(&buf))
+		Expect(ctx.Restore(other.Snapshot())).NotTo(Succeed())
+	})
+
+	Context("default context", func() {
+		It("preserves default context", func() {
+			def := logging.DefaultContext()
+			orig := def.(*logging.ContextReference).Context
+			level := def.GetDefaultLevel()
+			restore := logging.PreserveDefaultContext()
+
+			def.AddNamedRule("leak", logging.NewConditionRule(logging.DebugLevel, realm))
+			def.SetDefaultLevel(logging.TraceLevel)
+			logging.SetDefaultContext(ctx)
+			Expect(logging.DefaultContext().Logger(realm).Enabled(logging.DebugLevel)).To(BeFalse())
+
+			restore()
+			Expect(def.(*logging.ContextReference).Context).To(BeIdenticalTo(orig))
+			Expect(def.GetDefaultLevel()).To(Equal(level))
+			Expect(def.RemoveRule("leak")).To(BeFalse())
+		})
+	})
+})
@@ -18,9 +18,16 @@
 
 package logging
 
+import (
+	"strings"
+)
+
 type tag string
 
 // DefineTag creates a tag and registers it together with a description.
+// Tags may be structured hierarchically using dots or slashes, for example
+// net.http.client. The hierarchy of the defined tags can be
+// obtained with GetTagTree.
 func DefineTag(name string, desc string) Tag {
 	defs.DefineTag(name, desc)
 	return NewTag(name)
@@ -44,3 +51,90 @@ func (r tag) Match(messageContext ...MessageContext) bool {
 func (r tag) Name() string {
 	return string(r)
 }
+
+// isTagSeparator checks for the separators used for hierarchical tags.
+func isTagSeparator(c byte) bool {
+	return c == '.' || c == '/'
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+type tagprefix string
+
+// NewTagPrefix provides a new TagPrefix object to be used as rule condition
+// matching a tag hierarchy.
+func NewTagPrefix(name string) TagPrefix {
+	return tagprefix(name)
+}
+
+func (r tagprefix) Name() string {
+	return string(r)
+}
+
+func (r tagprefix) Match(messageContext ...MessageContext) bool {
+	for _, c := range messageContext {
+		if e, ok := c.(Tag); ok && r.matchName(e.Name()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r tagprefix) matchName(name string) bool {
+	if !strings.HasPrefix(name, string(r)) {
+		return false
+	}
+	return len(name) == len(r) || isTagSeparator(name[len(r)])
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// AnyTag is used as logging condition to match message
+// contexts with at least one of a set of tags.
+type AnyTag struct {
+	tags []string
+}
+
+var _ Condition = (*AnyTag)(nil)
+
+func NewAnyTag(tags ...string) *AnyTag {
+	return &AnyTag{tags: tags}
+}
+
+func (r *AnyTag) Tags() []string {
+	return sliceCopy(r.tags)
+}
+
+func (r *AnyTag) Match(messageContext ...MessageContext) bool {
+	for _, t := range r.tags {
+		if tag(t).Match(messageContext...) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllTags is used as logging condition to match message
+// contexts with all tags of a set of tags.
+type AllTags struct {
+	tags []string
+}
+
+var _ Condition = (*AllTags)(nil)
+
+func NewAllTags(tags ...string) *AllTags {
+	return &AllTags{tags: tags}
+}
+
+func (r *AllTags) Tags() []string {
+	return sliceCopy(r.tags)
+}
+
+func (r *AllTags) Match(messageContext ...MessageContext) bool {
+	for _, t := range r.tags {
+		if !tag(t).Match(messageContext...) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,101 @@
+/*
+ * Copyright 2023 Mandelsoft. All rights reserved.
+ *  This file is licensed under the Apache Software License, v. 2 except as noted
+ *  otherwise in the LICENSE file
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package logging_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/tonglil/buflogr"
+
+	"github.com/mandelsoft/logging"
+)
+
+var _ = Describe("tags", func() {
+	client := logging.NewTag("net.http.client")
+	server := logging.NewTag("net/http/server")
+	db := logging.NewTag("db")
+
+	It("matches tag hierarchies", func() {
+		Expect(logging.NewTagPrefix("net").Match(client)).To(BeTrue())
+		Expect(logging.NewTagPrefix("net").Match(server)).To(BeTrue())
+		Expect(logging.NewTagPrefix("net.http").Match(db, client)).To(BeTrue())
+		Expect(logging.NewTagPrefix("net.http.client").Match(client)).To(BeTrue())
+		Expect(logging.NewTagPrefix("net.ht").Match(client)).To(BeFalse())
+		Expect(logging.NewTagPrefix("net").Match(db)).To(BeFalse())
+		Expect(logging.NewTagPrefix("net").Match(logging.NewRealm("net"))).To(BeFalse())
+	})
+
+	It("matches any tag", func() {
+		c := logging.NewAnyTag("db", "net.http.client")
+		Expect(c.Match(client)).To(BeTrue())
+		Expect(c.Match(server, db)).To(BeTrue())
+		Expect(c.Match(server)).To(BeFalse())
+		Expect(logging.NewAnyTag().Match(db)).To(BeFalse())
+	})
+
+	It("matches all tags", func() {
+		c := logging.NewAllTags("db", "net.http.client")
+		Expect(c.Match(client)).To(BeFalse())
+		Expect(c.Match(db, server, client)).To(BeTrue())
+		Expect(logging.NewAllTags().Match()).To(BeTrue())
+	})
+
+	It("is used by rules", func() {
+		var buf bytes.Buffer
+
+		ctx := logging.New(buflogr.NewWithBuffer(&buf))
+		ctx.AddRule(logging.NewConditionRule(logging.DebugLevel, logging.NewTagPrefix("net")))
+
+		ctx.Logger(client).Debug("client")
+		ctx.Logger(db).Debug("db")
+		Expect(buf.String()).To(Equal("V[4] client\n"))
+		Expect(ctx.Explain(server).String()).To(ContainSubstring("Debug[tagprefix net]"))
+	})
+
+	It("provides the tag hierarchy", func() {
+		defs := logging.Definitions{
+			"net.http.client": []string{"http client"},
+			"net.http":        nil,
+			"net.grpc":        []string{"grpc", "remote calls"},
+			"db":              []string{"database"},
+		}
+		tree := defs.Tree()
+		Expect(tree.Children).To(HaveLen(2))
+		Expect(tree.Children[1]).To(Equal(&logging.DefinitionNode{
+			Name: "net",
+			Path: "net",
+			Children: []*logging.DefinitionNode{
+				{Name: "grpc", Path: "net.grpc", Defined: true, Descriptions: []string{"grpc", "remote calls"}},
+				{Name: "http", Path: "net.http", Defined: true, Children: []*logging.DefinitionNode{
+					{Name: "client", Path: "net.http.client", Defined: true, Descriptions: []string{"http client"}},
+				}},
+			},
+		}))
+		Expect("\n" + tree.String()).To(Equal(`
+db: database
+net
+  grpc: grpc; remote calls
+  http
+    client: http client
+`))
+	})
+})
@@ -0,0 +1,114 @@
+/*
+ * Copyright 2023 Mandelsoft. All rights reserved.
+ *  This file is licensed under the Apache Software License, v. 2 except as noted
+ *  otherwise in the LICENSE file
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package logging_test
+
+import (
+	"bytes"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/tonglil/buflogr"
+
+	"github.com/mandelsoft/logging"
+)
+
+var _ = Describe("temporary rules", func() {
+	var buf bytes.Buffer
+	var ctx logging.Context
+
+	realm := logging.NewRealm("realm")
+
+	BeforeEach(func() {
+		buf.Reset()
+		ctx = logging.New(buflogr.NewWithBuffer(&buf))
+	})
+
+	Context("expiring rules", func() {
+		It("removes expired rule", func() {
+			ctx.AddRule(logging.NewConditionRule(logging.DebugLevel, realm))
+			ctx.AddRule(logging.NewTemporaryRule(logging.NewConditionRule(logging.TraceLevel, realm), 100*time.Millisecond))
+			Expect(len(ctx.Rules())).To(Equal(2))
+
+			logger := logging.DynamicLogger(ctx, realm)
+			watermark := ctx.Tree().Updater().Watermark()
+			Expect(logger.Enabled(logging.TraceLevel)).To(BeTrue())
+
+			Eventually(func() int { return len(ctx.Rules()) }, time.Second, 10*time.Millisecond).Should(Equal(1))
+			Expect(ctx.Tree().Updater().Watermark()).To(BeNumerically(">", watermark))
+			Expect(logger.Enabled(logging.TraceLevel)).To(BeFalse())
+			Expect(logger.Enabled(logging.DebugLevel)).To(BeTrue())
+		})
+
+		It("does not match after expiry", func() {
+			rule := logging.NewExpiringRule(logging.NewConditionRule(logging.TraceLevel, realm), time.Now().Add(-time.Second))
+			Expect(rule.Match(nil, realm)).To(BeNil())
+		})
+
+		It("does not add expired rules", func() {
+			past := time.Now().Add(-time.Second)
+			ctx.AddRule(logging.NewConditionRule(logging.DebugLevel, realm))
+			ctx.AddNamedRule("named", logging.NewConditionRule(logging.DebugLevel, logging.NewTag("tag")))
+			ctx.AddRule(logging.NewExpiringRule(logging.NewConditionRule(logging.TraceLevel, realm), past))
+			ctx.AddRule(logging.NewTemporaryRule(logging.NewConditionRule(logging.TraceLevel, realm), 0))
+			Expect(len(ctx.Rules())).To(Equal(2))
+
+			ctx.AddNamedRule("named", logging.NewExpiringRule(logging.NewConditionRule(logging.TraceLevel, realm), past))
+			Expect(ctx.ReplaceRule("#1", logging.NewExpiringRule(logging.NewConditionRule(logging.TraceLevel, realm), past))).To(BeTrue())
+			Expect(ctx.Rules()).To(BeEmpty())
+
+			ctx.ReplaceRules(logging.InfoLevel, logging.NewExpiringRule(logging.NewConditionRule(logging.TraceLevel, realm), past))
+			Expect(ctx.Rules()).To(BeEmpty())
+			Expect(ctx.Logger(realm).Enabled(logging.TraceLevel)).To(BeFalse())
+		})
+
+		It("supersedes only expiring rules", func() {
+			ctx.AddRule(logging.NewConditionRule(logging.DebugLevel, realm))
+			ctx.AddRule(logging.NewTemporaryRule(logging.NewConditionRule(logging.TraceLevel, realm), time.Hour))
+			ctx.AddRule(logging.NewTemporaryRule(logging.NewConditionRule(logging.TraceLevel, realm), 2*time.Hour))
+			Expect(len(ctx.Rules())).To(Equal(2))
+			ctx.ResetRules()
+		})
+	})
+
+	Context("time window rules", func() {
+		day := time.Date(2023, 5, 1, 0, 0, 0, 0, time.Local)
+
+		It("handles window", func() {
+			rule := logging.NewTimeWindowRule(logging.NewConditionRule(logging.TraceLevel), 8*time.Hour, 17*time.Hour).(*logging.TimeWindowRule)
+			Expect(rule.Active(day.Add(7 * time.Hour))).To(BeFalse())
+			Expect(rule.Active(day.Add(8 * time.Hour))).To(BeTrue())
+			Expect(rule.Active(day.Add(17 * time.Hour))).To(BeFalse())
+			Expect(rule.NextChange(day.Add(7 * time.Hour))).To(Equal(day.Add(8 * time.Hour)))
+			Expect(rule.NextChange(day.Add(9 * time.Hour))).To(Equal(day.Add(17 * time.Hour)))
+			Expect(rule.NextChange(day.Add(18 * time.Hour))).To(Equal(day.AddDate(0, 0, 1).Add(8 * time.Hour)))
+			Expect(rule.Expired(day)).To(BeFalse())
+		})
+
+		It("handles window spanning midnight", func() {
+			rule := logging.NewTimeWindowRule(logging.NewConditionRule(logging.TraceLevel), 22*time.Hour, 6*time.Hour).(*logging.TimeWindowRule)
+			Expect(rule.Active(day.Add(23 * time.Hour))).To(BeTrue())
+			Expect(rule.Active(day.Add(5 * time.Hour))).To(BeTrue())
+			Expect(rule.Active(day.Add(12 * time.Hour))).To(BeFalse())
+			Expect(rule.NextChange(day.Add(12 * time.Hour))).To(Equal(day.Add(22 * time.Hour)))
+			Expect(rule.NextChange(day.Add(23 * time.Hour))).To(Equal(day.AddDate(0, 0, 1).Add(6 * time.Hour)))
+		})
+	})
+})
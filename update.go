@@ -19,47 +19,32 @@
 package logging
 
 import (
-	"sync"
 	"sync/atomic"
 )
 
 // UpdateState remembers the config level of a root logging context.
 type UpdateState struct {
-	generation atomic.Value
+	generation atomic.Int64
 }
 
 // Next provides the next generation number of a context tree.
 func (s *UpdateState) Next() int64 {
-	for {
-		old := s.generation.Load()
-
-		new := int64(1)
-		if old != nil {
-			new = old.(int64) + 1
-		}
-		if s.generation.CompareAndSwap(old, new) {
-			return new
-		}
-	}
+	return s.generation.Add(1)
 }
 
 // Generation returns the actual generation number of a context tree.
 func (s *UpdateState) Generation() int64 {
-	cur := s.generation.Load()
-	if cur == nil {
-		return 0
-	}
-	return cur.(int64)
+	return s.generation.Load()
 }
 
 // Updater is used by a logging context to check for new updates
 // in a context tree.
+// It is lock-free, so it can be used on hot paths.
 type Updater struct {
-	lock      sync.Mutex
 	state     *UpdateState
 	base      *Updater
-	watermark int64
-	seen      int64
+	watermark atomic.Int64
+	seen      atomic.Int64
 }
 
 func NewUpdater(base *Updater) *Updater {
@@ -68,51 +53,58 @@ func NewUpdater(base *Updater) *Updater {
 		u.state = &UpdateState{}
 	} else {
 		u.state = base.state
-		u.seen = base.SeenWatermark()
-		u.watermark = base.Watermark()
+		u.seen.Store(base.SeenWatermark())
+		u.watermark.Store(base.Watermark())
 	}
 	return u
 }
 
 func (u *Updater) Modify() {
-	u.lock.Lock()
-	defer u.lock.Unlock()
-
-	u.watermark = u.state.Next()
+	w := u.state.Next()
+	raise(&u.watermark, w)
 	if u.base == nil {
-		u.seen = u.watermark
+		raise(&u.seen, w)
 	}
 }
 
 func (u *Updater) Watermark() int64 {
-	u.lock.Lock()
-	defer u.lock.Unlock()
 	if u.base != nil {
-		w := u.base.Watermark()
-		if w > u.watermark {
-			u.watermark = w
-		}
+		return raise(&u.watermark, u.base.Watermark())
 	}
-	return u.watermark
+	return u.watermark.Load()
 }
 
 func (u *Updater) SeenWatermark() int64 {
-	u.lock.Lock()
-	defer u.lock.Unlock()
-	return u.seen
+	return u.seen.Load()
 }
 
 // Require returns whether a local config update is required.
 func (u *Updater) Require() bool {
-	u.lock.Lock()
-	defer u.lock.Unlock()
 	if u.base == nil {
 		return false
 	}
 	w := u.base.Watermark()
-	if w > u.seen {
-		u.seen = w
-		return true
+	for {
+		seen := u.seen.Load()
+		if w <= seen {
+			return false
+		}
+		if u.seen.CompareAndSwap(seen, w) {
+			return true
+		}
+	}
+}
+
+// raise sets the given value to w, if w is larger than the actual value.
+// It returns the resulting value.
+func raise(v *atomic.Int64, w int64) int64 {
+	for {
+		old := v.Load()
+		if w <= old {
+			return old
+		}
+		if v.CompareAndSwap(old, w) {
+			return w
+		}
 	}
-	return false
 }
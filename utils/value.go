@@ -49,7 +49,26 @@ func (n ignoreKeyPair) String() string {
 // Its string representation is "<unset>"-
 var Ignore = &ignoreKeyPair{}
 
+// Lazy is a field value, which is determined by calling the function
+// not before the log record is finally emitted. This can be used to
+// avoid the calculation of expensive values for disabled log levels.
+// If the function returns Ignore, the field is omitted.
+//
+// Like Ignore, this value is only considered as special value for the
+// logging functions of this module, or if a logr sink or message formatter
+// of this module is used.
+type Lazy func() interface{}
+
+// Resolve determines the effective value for a potentially lazy value.
+func Resolve(v interface{}) interface{} {
+	if l, ok := v.(Lazy); ok {
+		return l()
+	}
+	return v
+}
+
 func FieldValue(formatter func(interface{}) string, v interface{}) interface{} {
+	v = Resolve(v)
 	if v == Ignore {
 		return v
 	}
@@ -0,0 +1,176 @@
+/*
+ * Copyright 2023 Mandelsoft. All rights reserved.
+ *  This file is licensed under the Apache Software License, v. 2 except as noted
+ *  otherwise in the LICENSE file
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package logging
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ChangeType describes the kind of a configuration change
+// of a logging context.
+type ChangeType string
+
+const (
+	// ChangeRuleAdded indicates rules added to the rule set.
+	ChangeRuleAdded ChangeType = "rule added"
+	// ChangeRuleRemoved indicates rules removed from the rule set,
+	// explicitly, by superseding rules or by expiry.
+	ChangeRuleRemoved ChangeType = "rule removed"
+	// ChangeDefaultLevel indicates a change of the default level.
+	ChangeDefaultLevel ChangeType = "default level"
+	// ChangeBaseLogger indicates a change of the base logger.
+	ChangeBaseLogger ChangeType = "base logger"
+	// ChangeNamedSink indicates a change of a named sink.
+	ChangeNamedSink ChangeType = "named sink"
+	// ChangeFilters indicates a change of the filters.
+	ChangeFilters ChangeType = "filters"
+)
+
+// ChangeEvent describes a configuration change of a logging context.
+type ChangeEvent struct {
+	// Type is the kind of the change.
+	Type ChangeType
+	// Context is the modified logging context. For changes inherited
+	// from a base context, this is the base context.
+	Context Context
+	// RuleIds are the ids of the added or removed rules.
+	RuleIds []string
+	// Level is the new configured default level for
+	// ChangeDefaultLevel events. A negative level
+	// indicates the usage of the level of the base context.
+	Level int
+	// SinkName is the name of the sink for ChangeNamedSink events.
+	SinkName string
+}
+
+func (e ChangeEvent) String() string {
+	msg := fmt.Sprintf("%s (context %s)", e.Type, describeContext(e.Context))
+	switch e.Type {
+	case ChangeRuleAdded, ChangeRuleRemoved:
+		msg += ": " + strings.Join(e.RuleIds, ", ")
+	case ChangeDefaultLevel:
+		msg += ": " + LevelName(e.Level)
+	case ChangeNamedSink:
+		msg += ": " + e.SinkName
+	}
+	return msg
+}
+
+type watcher struct {
+	id int
+	f  func(ChangeEvent)
+}
+
+// watchers manages the change watchers of a logging context.
+type watchers struct {
+	lock     sync.Mutex
+	seq      int
+	watchers []watcher
+	// cancel cancels the registration at the base context.
+	cancel func()
+}
+
+// Watch registers a function called for every configuration change
+// of the context or one of its base contexts. The function is called
+// synchronously after the change has been published, so it should not
+// block. It is called without holding any lock of the context, so it is
+// allowed to access or modify the context.
+// The registration is removed by calling the returned function.
+func (c *context) Watch(f func(ChangeEvent)) func() {
+	w := &c.watchers
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if len(w.watchers) == 0 {
+		if c.base != nil {
+			// changes of the base context are only observed
+			// as long as there are local watchers.
+			w.cancel = c.base.Watch(c.dispatch)
+		}
+	}
+	w.seq++
+	id := w.seq
+	// copy-on-write to enable lock-free dispatching
+	w.watchers = append(sliceCopy(w.watchers), watcher{id, f})
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { c.unwatch(id) })
+	}
+}
+
+func (c *context) unwatch(id int) {
+	w := &c.watchers
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	list := make([]watcher, 0, len(w.watchers))
+	for _, e := range w.watchers {
+		if e.id != id {
+			list = append(list, e)
+		}
+	}
+	w.watchers = list
+	if len(w.watchers) == 0 && w.cancel != nil {
+		w.cancel()
+		w.cancel = nil
+	}
+}
+
+// dispatch calls the registered watchers for an event.
+func (c *context) dispatch(e ChangeEvent) {
+	w := &c.watchers
+	w.lock.Lock()
+	list := w.watchers
+	w.lock.Unlock()
+
+	for _, r := range list {
+		r.f(e)
+	}
+}
+
+// changed records a change event to be dispatched by unlock.
+// It must be called with the lock held.
+func (c *context) changed(t ChangeType, modify ...func(e *ChangeEvent)) {
+	e := ChangeEvent{Type: t, Context: c}
+	for _, m := range modify {
+		m(&e)
+	}
+	c.pending = append(c.pending, e)
+}
+
+func (c *context) rulesChanged(t ChangeType, ids []string) {
+	if len(ids) > 0 {
+		c.changed(t, func(e *ChangeEvent) { e.RuleIds = ids })
+	}
+}
+
+// unlock releases the lock and dispatches the
+// change events recorded during the modification.
+func (c *context) unlock() {
+	events := c.pending
+	c.pending = nil
+	c.lock.Unlock()
+
+	for _, e := range events {
+		c.dispatch(e)
+	}
+}
@@ -0,0 +1,145 @@
+/*
+ * Copyright 2023 Mandelsoft. All rights reserved.
+ *  This file is licensed under the Apache Software License, v. 2 except as noted
+ *  otherwise in the LICENSE file
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package logging_test
+
+import (
+	"bytes"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/tonglil/buflogr"
+
+	"github.com/mandelsoft/logging"
+)
+
+var _ = Describe("change notifications", func() {
+	var buf bytes.Buffer
+	var ctx logging.Context
+	var events []logging.ChangeEvent
+
+	realm := logging.NewRealm("realm")
+	tag := logging.NewTag("tag")
+
+	watch := func(e logging.ChangeEvent) {
+		events = append(events, e)
+	}
+
+	BeforeEach(func() {
+		buf.Reset()
+		events = nil
+		ctx = logging.New(buflogr.NewWithBuffer(&buf))
+	})
+
+	It("reports rule changes", func() {
+		cancel := ctx.Watch(watch)
+		defer cancel()
+
+		ctx.AddRule(logging.NewConditionRule(logging.DebugLevel, realm), logging.NewConditionRule(logging.DebugLevel, tag))
+		ctx.AddRule(logging.NewConditionRule(logging.TraceLevel, realm))
+		ctx.RemoveRule("#2")
+
+		Expect(len(events)).To(Equal(4))
+		Expect(events[0].Type).To(Equal(logging.ChangeRuleAdded))
+		Expect(events[0].RuleIds).To(Equal([]string{"#1", "#2"}))
+		Expect(events[0].Context).To(BeIdenticalTo(ctx))
+		Expect(events[1].Type).To(Equal(logging.ChangeRuleRemoved))
+		Expect(events[1].RuleIds).To(Equal([]string{"#1"}))
+		Expect(events[2].Type).To(Equal(logging.ChangeRuleAdded))
+		Expect(events[2].RuleIds).To(Equal([]string{"#3"}))
+		Expect(events[3].Type).To(Equal(logging.ChangeRuleRemoved))
+		Expect(events[3].RuleIds).To(Equal([]string{"#2"}))
+	})
+
+	It("reports level and logger changes", func() {
+		cancel := ctx.Watch(watch)
+		defer cancel()
+
+		ctx.SetDefaultLevel(logging.DebugLevel)
+		ctx.SetBaseLogger(buflogr.NewWithBuffer(&buf))
+		ctx.SetNamedSink("sink", buflogr.NewWithBuffer(&buf))
+
+		Expect(len(events)).To(Equal(3))
+		Expect(events[0].String()).To(MatchRegexp(`^default level \(context [0-9]+\): Debug$`))
+		Expect(events[1].Type).To(Equal(logging.ChangeBaseLogger))
+		Expect(events[2].Type).To(Equal(logging.ChangeNamedSink))
+		Expect(events[2].SinkName).To(Equal("sink"))
+	})
+
+	It("reports level changes of rule replacements, only", func() {
+		ctx.AddRule(logging.NewConditionRule(logging.DebugLevel, realm))
+		cancel := ctx.Watch(watch)
+		defer cancel()
+
+		ctx.ReplaceRules(logging.InfoLevel, logging.NewConditionRule(logging.TraceLevel, realm))
+		Expect(len(events)).To(Equal(2))
+		Expect(events[0].Type).To(Equal(logging.ChangeRuleRemoved))
+		Expect(events[1].Type).To(Equal(logging.ChangeRuleAdded))
+
+		events = nil
+		ctx.ReplaceRules(logging.DebugLevel)
+		Expect(len(events)).To(Equal(2))
+		Expect(events[0].Type).To(Equal(logging.ChangeDefaultLevel))
+		Expect(events[0].Level).To(Equal(logging.DebugLevel))
+	})
+
+	It("reports inherited changes", func() {
+		nested := logging.NewWithBase(ctx)
+		cancel := nested.Watch(watch)
+
+		ctx.SetDefaultLevel(logging.DebugLevel)
+		nested.SetDefaultLevel(logging.TraceLevel)
+		Expect(len(events)).To(Equal(2))
+		Expect(events[0].Context).To(BeIdenticalTo(ctx))
+		Expect(events[1].Context).To(BeIdenticalTo(nested))
+
+		cancel()
+		cancel()
+		ctx.SetDefaultLevel(logging.InfoLevel)
+		nested.SetDefaultLevel(logging.DebugLevel)
+		Expect(len(events)).To(Equal(2))
+	})
+
+	It("allows modifications by watchers", func() {
+		cancel := ctx.Watch(func(e logging.ChangeEvent) {
+			if e.Type == logging.ChangeRuleAdded {
+				ctx.SetDefaultLevel(logging.DebugLevel)
+			}
+		})
+		defer cancel()
+
+		ctx.AddRule(logging.NewConditionRule(logging.TraceLevel, realm))
+		Expect(ctx.GetDefaultLevel()).To(Equal(logging.DebugLevel))
+	})
+
+	It("reports expired rules", func() {
+		ch := make(chan logging.ChangeEvent, 10)
+		cancel := ctx.Watch(func(e logging.ChangeEvent) { ch <- e })
+		defer cancel()
+
+		ctx.AddRule(logging.NewTemporaryRule(logging.NewConditionRule(logging.DebugLevel, realm), 50*time.Millisecond))
+		Expect((<-ch).Type).To(Equal(logging.ChangeRuleAdded))
+
+		var e logging.ChangeEvent
+		Eventually(ch).Should(Receive(&e))
+		Expect(e.Type).To(Equal(logging.ChangeRuleRemoved))
+		Expect(e.RuleIds).To(Equal([]string{"#1"}))
+	})
+})